@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"uptime-go/internal/configuration"
+	"uptime-go/internal/helper"
+	"uptime-go/internal/models"
+	"uptime-go/internal/monitor"
+	"uptime-go/pkg/log"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkTimeout               string
+	checkCertificateMonitoring bool
+	checkDegradedThreshold     string
+)
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check <url|id|name>",
+	Short: "Check a website once and print the result",
+	Long: `The 'check' command runs a single check without recording it. A URL is
+checked with the options given as flags, without reading the configuration.
+A configured monitor ID or name is checked with the monitor's own options,
+flags override them.
+
+Example:
+  uptime-go check https://www.example.com --timeout 10s
+  uptime-go check example-eu`,
+	Args: cobra.ExactArgs(1),
+	// The configuration is only needed to check a configured monitor
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		log.InitLogger(logPath)
+		log.SetLogLevel(logLevel)
+
+		if len(args) == 0 || isCheckURL(args[0]) {
+			return nil
+		}
+
+		return configuration.Load(configPath, agentConfigPath, standalone)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		url := helper.NormalizeURL(args[0])
+
+		target := &models.Monitor{
+			URL:                   url,
+			ResponseTimeThreshold: helper.ParseDuration(checkTimeout, "30s"),
+		}
+		if !isCheckURL(args[0]) {
+			for _, cfg := range configuration.Config.Monitor {
+				if cfg.ID == args[0] || (cfg.Name != "" && cfg.Name == args[0]) || cfg.URL == url {
+					copied := *cfg
+					target = &copied
+					break
+				}
+			}
+		}
+
+		if cmd.Flags().Changed("timeout") {
+			target.ResponseTimeThreshold = helper.ParseDuration(checkTimeout, "30s")
+		}
+		if cmd.Flags().Changed("certificate-monitoring") {
+			target.CertificateMonitoring = checkCertificateMonitoring
+		}
+		if cmd.Flags().Changed("degraded-threshold") {
+			target.DegradedThreshold = helper.ParseDuration(checkDegradedThreshold, "")
+		}
+
+		url = target.URL
+		check := monitor.CheckOnce(target)
+		models.Response{Message: "check completed", Data: check}.Print()
+
+		if !check.IsUp {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%s is down", url)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+
+	checkCmd.Flags().StringVar(&checkTimeout, "timeout", "30s", "Response time threshold before the check times out")
+	checkCmd.Flags().BoolVar(&checkCertificateMonitoring, "certificate-monitoring", false, "Verify the TLS certificate")
+	checkCmd.Flags().StringVar(&checkDegradedThreshold, "degraded-threshold", "", "Response time above which the website is reported as degraded")
+}
+
+// isCheckURL reports whether the argument of check is a URL rather than a
+// monitor ID or name. URLs have a scheme, like monitor references.
+func isCheckURL(arg string) bool {
+	return strings.Contains(arg, "://")
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"uptime-go/internal/configuration"
+	"uptime-go/internal/models"
+	"uptime-go/pkg/log"
+
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the configuration file",
+	// The configuration is not loaded, so these commands also work when it
+	// is broken
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		log.InitLogger(logPath)
+		log.SetLogLevel(logLevel)
+		return nil
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Strictly validate the configuration file",
+	Long: `The 'validate' command reports every problem in the configuration file and
+the files it includes with its line and column: syntax errors, unknown
+fields, invalid durations,
+duplicate URLs and thresholds that can never be reached, e.g. a
+response_time_threshold not below the interval. The service itself falls
+back to defaults for such values, so run this in CI before deploying.
+
+It exits with a non-zero status when problems are found.
+
+Example:
+  uptime-go config validate --config configs/uptime.yml`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		problems, err := configuration.ValidateFile(configPath)
+		if err != nil {
+			return err
+		}
+
+		if len(problems) == 0 {
+			models.Response{Message: "configuration is valid"}.Print()
+			return nil
+		}
+
+		for _, problem := range problems {
+			fmt.Fprintln(os.Stderr, problem.Error())
+		}
+
+		cmd.SilenceUsage = true
+		if len(problems) == 1 {
+			return fmt.Errorf("1 problem found in %s", configPath)
+		}
+		return fmt.Errorf("%d problems found in %s", len(problems), configPath)
+	},
+}
+
+var configBackupsCmd = &cobra.Command{
+	Use:   "backups",
+	Short: "List configuration backups, newest first",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backups, err := configuration.ListBackups(configPath)
+		if err != nil {
+			return err
+		}
+
+		models.Response{Message: "configuration backups", Data: backups}.Print()
+		return nil
+	},
+}
+
+var configRollbackCmd = &cobra.Command{
+	Use:   "rollback [backup]",
+	Short: "Restore a configuration backup, the latest one by default",
+	Long: `The 'rollback' command restores a backup made when the configuration was
+updated. The current configuration is backed up first, so a rollback can be
+undone. Restart the service to apply the restored configuration.
+
+Example:
+  uptime-go config rollback
+  uptime-go config rollback config.yml.20250815T162505.930614.bak`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var name string
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		backup, err := configuration.Rollback(configPath, name)
+		if err != nil {
+			return err
+		}
+
+		models.Response{Message: "configuration restored, restart to apply", Data: backup}.Print()
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd, configBackupsCmd, configRollbackCmd)
+}
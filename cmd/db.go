@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"uptime-go/internal/models"
+	"uptime-go/internal/net/database"
+	"uptime-go/pkg/log"
+
+	"github.com/spf13/cobra"
+)
+
+var dbNoBackup bool
+
+// dbCmd represents the db command
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage the database schema",
+	// The configuration is not needed to migrate the database
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		log.InitLogger(logPath)
+		log.SetLogLevel(logLevel)
+		return nil
+	},
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply the pending database migrations",
+	Long: `The 'migrate' command applies the pending database migrations. The service
+also applies them when it starts. An existing database is backed up next to
+its file first.
+
+Example:
+  uptime-go db migrate --database /var/lib/uptime-go/uptime.db`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := database.Open(databasePath)
+		if err != nil {
+			return err
+		}
+
+		applied, err := db.Migrate()
+		if err != nil {
+			return err
+		}
+
+		if len(applied) == 0 {
+			models.Response{Message: "database is up to date"}.Print()
+			return nil
+		}
+
+		models.Response{Message: "migrations applied", Data: applied}.Print()
+		return nil
+	},
+}
+
+var dbStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List the database migrations and when they were applied",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := database.Open(databasePath)
+		if err != nil {
+			return err
+		}
+
+		statuses, err := db.MigrationStatus()
+		if err != nil {
+			return err
+		}
+
+		version, err := db.SchemaVersion()
+		if err != nil {
+			return err
+		}
+
+		models.Response{
+			Message: fmt.Sprintf("schema version %d of %d", version, database.LatestSchemaVersion()),
+			Data:    statuses,
+		}.Print()
+		return nil
+	},
+}
+
+var dbRollbackCmd = &cobra.Command{
+	Use:   "rollback [version]",
+	Short: "Revert database migrations, the latest one by default",
+	Long: `The 'rollback' command reverts the migrations applied after the given schema
+version, newest first, or the latest migration without one. The database is
+backed up next to its file first. PostgreSQL databases are not backed up:
+back them up with pg_dump and pass --no-backup. Stop the service before
+rolling back, it applies the pending migrations again when it starts.
+
+Example:
+  uptime-go db rollback
+  uptime-go db rollback 1
+  uptime-go --database postgres://db.example.com/uptime db rollback --no-backup`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := database.Open(databasePath)
+		if err != nil {
+			return err
+		}
+
+		version, err := db.SchemaVersion()
+		if err != nil {
+			return err
+		}
+		version = max(version-1, 0)
+
+		if len(args) > 0 {
+			if version, err = strconv.Atoi(args[0]); err != nil {
+				return fmt.Errorf("invalid schema version %q", args[0])
+			}
+		}
+
+		reverted, err := db.Rollback(version, !dbNoBackup)
+		if errors.Is(err, database.ErrBackupUnsupported) {
+			return fmt.Errorf("%w, then roll back with --no-backup", err)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(reverted) == 0 {
+			models.Response{Message: "nothing to roll back"}.Print()
+			return nil
+		}
+
+		models.Response{Message: "migrations reverted", Data: reverted}.Print()
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dbCmd)
+	dbCmd.AddCommand(dbMigrateCmd, dbStatusCmd, dbRollbackCmd)
+	dbRollbackCmd.Flags().BoolVar(&dbNoBackup, "no-backup", false, "Roll back without backing up the database first")
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+	"uptime-go/internal/monitor"
+	"uptime-go/internal/net/database"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	incidentActor string
+	incidentNote  string
+
+	incidentFilter database.IncidentFilter
+	incidentType   string
+	incidentFrom   string
+	incidentTo     string
+)
+
+// incidentsCmd represents the incidents command
+var incidentsCmd = &cobra.Command{
+	Use:   "incidents",
+	Short: "List recorded incidents",
+	Long: `The 'incidents' command lists recorded incidents, newest first.
+
+Example:
+  uptime-go incidents --monitor https://www.example.com --state open
+  uptime-go incidents --from 2025-08-01T00:00:00Z --sort created_at`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter := incidentFilter
+		filter.Type = incident.Type(incidentType)
+
+		for _, bound := range []struct {
+			value  string
+			target **time.Time
+		}{{incidentFrom, &filter.From}, {incidentTo, &filter.To}} {
+			if bound.value == "" {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339, bound.value)
+			if err != nil {
+				return fmt.Errorf("invalid time %q, expected RFC 3339: %w", bound.value, err)
+			}
+			*bound.target = &t
+		}
+
+		if filter.State != "" && filter.State != database.IncidentStateOpen && filter.State != database.IncidentStateResolved {
+			return fmt.Errorf("invalid state %q, expected %s or %s", filter.State, database.IncidentStateOpen, database.IncidentStateResolved)
+		}
+
+		db, err := database.New(databasePath)
+		if err != nil {
+			return err
+		}
+
+		incidents, total, err := db.ListIncidents(filter)
+		if err != nil {
+			return err
+		}
+
+		models.Response{Message: fmt.Sprintf("%d of %d incidents", len(incidents), total), Data: incidents}.Print()
+		return nil
+	},
+}
+
+// incidentCmd represents the incident command
+var incidentCmd = &cobra.Command{
+	Use:   "incident",
+	Short: "Inspect and manage recorded incidents",
+}
+
+var incidentShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show an incident with its timeline",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := database.New(databasePath)
+		if err != nil {
+			return err
+		}
+
+		inc, err := db.GetIncident(args[0])
+		if err != nil {
+			return err
+		}
+
+		models.Response{Message: "incident", Data: inc}.Print()
+		return nil
+	},
+}
+
+var incidentAckCmd = &cobra.Command{
+	Use:   "ack <id>",
+	Short: "Acknowledge an open incident",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := database.New(databasePath)
+		if err != nil {
+			return err
+		}
+
+		inc, err := db.AcknowledgeIncident(args[0], incidentActor, incidentNote)
+		if err != nil {
+			return err
+		}
+
+		monitor.SyncIncidentStatus(db, inc, incident.OnInvestigation, incidentActor)
+
+		models.Response{Message: "incident acknowledged", Data: inc}.Print()
+		return nil
+	},
+}
+
+var incidentNoteCmd = &cobra.Command{
+	Use:   "note <id> <message>",
+	Short: "Attach a note to an incident",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := database.New(databasePath)
+		if err != nil {
+			return err
+		}
+
+		inc, err := db.AddIncidentNote(args[0], incidentActor, args[1])
+		if err != nil {
+			return err
+		}
+
+		models.Response{Message: "note added", Data: inc}.Print()
+		return nil
+	},
+}
+
+var incidentFalsePositiveCmd = &cobra.Command{
+	Use:   "false-positive <id>",
+	Short: "Close an incident as a false positive",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := database.New(databasePath)
+		if err != nil {
+			return err
+		}
+
+		inc, err := db.MarkIncidentFalsePositive(args[0], incidentActor, incidentNote)
+		if err != nil {
+			return err
+		}
+
+		monitor.SyncIncidentStatus(db, inc, incident.FalsePositive, incidentActor)
+
+		models.Response{Message: "incident marked as false positive", Data: inc}.Print()
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(incidentCmd, incidentsCmd)
+	incidentCmd.AddCommand(incidentShowCmd, incidentAckCmd, incidentNoteCmd, incidentFalsePositiveCmd)
+
+	defaultActor := os.Getenv("USER")
+	if defaultActor == "" {
+		defaultActor = "cli"
+	}
+
+	incidentCmd.PersistentFlags().StringVar(&incidentActor, "by", defaultActor, "Name recorded as the author of the action")
+	incidentAckCmd.Flags().StringVar(&incidentNote, "note", "", "Optional note recorded with the acknowledgement")
+	incidentFalsePositiveCmd.Flags().StringVar(&incidentNote, "note", "", "Optional note recorded with the status change")
+
+	incidentsCmd.Flags().StringVar(&incidentFilter.Monitor, "monitor", "", "Monitor ID, URL or name")
+	incidentsCmd.Flags().StringVar(&incidentType, "type", "", "Incident type, e.g. timeout")
+	incidentsCmd.Flags().StringVar(&incidentFilter.State, "state", "", "open or resolved")
+	incidentsCmd.Flags().StringVar(&incidentFrom, "from", "", "Only incidents created at or after this time (RFC 3339)")
+	incidentsCmd.Flags().StringVar(&incidentTo, "to", "", "Only incidents created before this time (RFC 3339)")
+	incidentsCmd.Flags().StringVar(&incidentFilter.Sort, "sort", "-created_at", "Sort key: created_at, solved_at or type, prefixed with - for descending order")
+	incidentsCmd.Flags().IntVar(&incidentFilter.Limit, "limit", 50, "Maximum number of incidents, 0 for no limit")
+	incidentsCmd.Flags().IntVar(&incidentFilter.Offset, "offset", 0, "Number of incidents to skip")
+}
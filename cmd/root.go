@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"os"
+	"strconv"
 
 	"uptime-go/internal/configuration"
 	"uptime-go/pkg/log"
@@ -12,10 +13,12 @@ import (
 const VERSION = "0.3.0"
 
 var (
-	configPath   string
-	databasePath string
-	logLevel     string
-	logPath      string
+	configPath      string
+	agentConfigPath string
+	standalone      bool
+	databasePath    string
+	logLevel        string
+	logPath         string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -32,7 +35,7 @@ Usage: uptime-go [--config=path/to/uptime.yaml] run`,
 		log.InitLogger(logPath)
 		log.SetLogLevel(logLevel)
 
-		if err := configuration.Load(configPath); err != nil {
+		if err := configuration.Load(configPath, agentConfigPath, standalone); err != nil {
 			return err
 		}
 
@@ -51,7 +54,26 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "/etc/uptime-go/config.yml", "Path to configuration file")
-	rootCmd.PersistentFlags().StringVarP(&databasePath, "database", "d", "/var/lib/uptime-go/uptime.db", "Path to database file")
+	rootCmd.PersistentFlags().StringVar(&agentConfigPath, "agent-config", envOrDefault("UPTIME_AGENT_CONFIG", configuration.OJTGUARDIAN_CONFIG), "Path to the ojtguardian agent configuration file (env UPTIME_AGENT_CONFIG)")
+	rootCmd.PersistentFlags().BoolVar(&standalone, "standalone", envBool("UPTIME_STANDALONE"), "Run without reporting incidents to the master (env UPTIME_STANDALONE)")
+	rootCmd.PersistentFlags().StringVarP(&databasePath, "database", "d", envOrDefault("UPTIME_DATABASE", "/var/lib/uptime-go/uptime.db"), "Path to the SQLite database file or PostgreSQL DSN (env UPTIME_DATABASE)")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "Log level (debug, info, warn, error)")
 	rootCmd.PersistentFlags().StringVar(&logPath, "log-path", "", "Path to log file")
 }
+
+// envOrDefault returns the value of the environment variable name, or
+// fallback when it is unset or empty.
+func envOrDefault(name string, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+// envBool returns the environment variable name parsed as a boolean, false
+// when it is unset or invalid.
+func envBool(name string) bool {
+	value, _ := strconv.ParseBool(os.Getenv(name))
+	return value
+}
@@ -7,7 +7,7 @@ import (
 	"syscall"
 	"uptime-go/internal/api"
 	"uptime-go/internal/configuration"
-	"uptime-go/internal/helper"
+	"uptime-go/internal/models"
 	"uptime-go/internal/monitor"
 	"uptime-go/internal/net"
 	"uptime-go/internal/net/database"
@@ -37,34 +37,51 @@ Example:
 		configs := configuration.Config.Monitor
 
 		log.Debug().
-			Any("monitor", configs).
-			Str("master_url", configuration.Config.Agent.MasterHost).
+			Any("config", configuration.Effective()).
 			Msg("configuration")
 
-		var urls []string
+		var ids []string
 
 		for _, r := range configs {
-			r.ID = helper.GenerateRandomID()
-			urls = append(urls, r.URL)
+			ids = append(ids, r.ID)
 		}
 
 		// Initialize database
 		db, err := database.New(databasePath)
 		if err != nil {
-			log.Error().Err(err).Str("database_path", databasePath).Msg("Error initializing database")
+			log.Error().Err(err).Str("database", database.RedactDSN(databasePath)).Msg("Error initializing database")
 			return err
 		}
 
+		// Sync maintenance windows declared in the config file
+		if err := db.ReplaceMaintenance(models.MaintenanceSourceConfig, configuration.Config.Maintenance); err != nil {
+			log.Error().Err(err).Msg("Failed to sync maintenance windows")
+		}
+
+		// Keep the histories of monitors stored under a previous ID
+		if err := db.AdoptMonitorIDs(configs); err != nil {
+			log.Error().Err(err).Msg("Failed to migrate monitor IDs")
+		}
+
 		// Merge config
-		db.UpsertRecord(configs, "url", &[]string{
+		db.UpsertRecord(configs, "id", &[]string{
 			"url",
+			"name",
+			"group_name",
+			"public",
 			"enabled",
 			"response_time_threshold",
 			"interval",
 			"certificate_monitoring",
 			"certificate_expired_before",
+			"depends_on",
+			"degraded_threshold",
+			"degraded_average",
+			"anomaly_detection",
+			"tags",
+			"labels",
 		})
-		db.DB.Where("url IN ?", urls).Find(&configs)
+		db.DB.Where("id IN ?", ids).Find(&configs)
 
 		// Initialize and start monitor
 		uptimeMonitor, err := monitor.NewUptimeMonitor(db, configs)
@@ -96,6 +113,7 @@ Example:
 				Bind:       apiBind,
 				Port:       apiPort,
 				ConfigPath: configPath,
+				Monitor:    uptimeMonitor,
 			}, db)
 
 			go func() {
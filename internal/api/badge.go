@@ -0,0 +1,179 @@
+package api
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"time"
+	"uptime-go/internal/helper"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const badgeMaxAge = 60 // seconds
+
+const (
+	colorGreen      = "#4c1"
+	colorYellowish  = "#97ca00"
+	colorYellow     = "#dfb317"
+	colorRed        = "#e05d44"
+	colorBlue       = "#007ec6"
+	colorGrey       = "#9f9f9f"
+	badgeCharWidth  = 7
+	badgeTextMargin = 10
+)
+
+type BadgeQueryParams struct {
+	Label  string `form:"label"`
+	Window string `form:"window" binding:"omitempty,duration"`
+}
+
+// badgeTemplate is a flat badge in the style of shields.io.
+const badgeTemplate = `<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[3]s: %[4]s">
+<title>%[3]s: %[4]s</title>
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[6]d" height="20" fill="%[5]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="%[7]d" y="15" fill="#010101" fill-opacity=".3">%[3]s</text><text x="%[7]d" y="14">%[3]s</text>
+<text x="%[8]d" y="15" fill="#010101" fill-opacity=".3">%[4]s</text><text x="%[8]d" y="14">%[4]s</text>
+</g>
+</svg>`
+
+func renderBadge(label string, value string, color string) string {
+	labelWidth := len([]rune(label))*badgeCharWidth + badgeTextMargin
+	valueWidth := len([]rune(value))*badgeCharWidth + badgeTextMargin
+
+	return fmt.Sprintf(badgeTemplate,
+		labelWidth+valueWidth,
+		labelWidth,
+		html.EscapeString(label),
+		html.EscapeString(value),
+		color,
+		valueWidth,
+		labelWidth/2,
+		labelWidth+valueWidth/2,
+	)
+}
+
+func (s *Server) StatusBadgeHandler(c *gin.Context) {
+	s.badge(c, "status", func(c *gin.Context, query BadgeQueryParams) (string, string, error) {
+		monitor, err := s.db.GetMonitor(c.Param("monitor"))
+		if err != nil {
+			return "", "", err
+		}
+
+		windows, err := s.db.GetAllMaintenance()
+		if err != nil {
+			return "", "", err
+		}
+
+		switch monitorStatus(*monitor, windows, time.Now()) {
+		case "up":
+			return "up", colorGreen, nil
+		case "degraded":
+			return "degraded", colorYellow, nil
+		case "down":
+			return "down", colorRed, nil
+		case "maintenance":
+			return "maintenance", colorBlue, nil
+		default:
+			return "unknown", colorGrey, nil
+		}
+	})
+}
+
+func (s *Server) UptimeBadgeHandler(c *gin.Context) {
+	s.badge(c, "uptime", func(c *gin.Context, query BadgeQueryParams) (string, string, error) {
+		monitor, err := s.db.GetMonitor(c.Param("monitor"))
+		if err != nil {
+			return "", "", err
+		}
+
+		window := helper.ParseDuration(query.Window, "30d")
+		uptime, err := s.db.GetUptime(monitor.ID, time.Now().Add(-window), true)
+		if err != nil {
+			return "", "", err
+		}
+
+		if uptime == nil {
+			return "no data", colorGrey, nil
+		}
+
+		color := colorRed
+		switch {
+		case *uptime >= 99.9:
+			color = colorGreen
+		case *uptime >= 99:
+			color = colorYellowish
+		case *uptime >= 95:
+			color = colorYellow
+		}
+
+		return fmt.Sprintf("%.2f%%", *uptime), color, nil
+	})
+}
+
+func (s *Server) ResponseTimeBadgeHandler(c *gin.Context) {
+	s.badge(c, "response time", func(c *gin.Context, query BadgeQueryParams) (string, string, error) {
+		monitor, err := s.db.GetMonitor(c.Param("monitor"))
+		if err != nil {
+			return "", "", err
+		}
+
+		if monitor.ResponseTime == nil || monitor.IsUp == nil || !*monitor.IsUp {
+			return "n/a", colorGrey, nil
+		}
+
+		color := colorGreen
+		if monitor.IsDegraded != nil && *monitor.IsDegraded {
+			color = colorYellow
+		}
+
+		return fmt.Sprintf("%dms", *monitor.ResponseTime), color, nil
+	})
+}
+
+// badge renders the value returned by resolve as an SVG badge with caching
+// headers. Unknown monitors get a grey badge with a 404 status so embedded
+// images still render.
+func (s *Server) badge(c *gin.Context, label string, resolve func(*gin.Context, BadgeQueryParams) (string, string, error)) {
+	var query BadgeQueryParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindingError(c, "Invalid query parameters", err)
+		return
+	}
+
+	if query.Label != "" {
+		label = query.Label
+	}
+
+	status := http.StatusOK
+	value, color, err := resolve(c, query)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to render badge", "error": err.Error()})
+			return
+		}
+		status = http.StatusNotFound
+		value, color = "not found", colorGrey
+	}
+
+	svg := renderBadge(label, value, color)
+	sum := sha1.Sum([]byte(svg))
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d, must-revalidate", badgeMaxAge))
+	c.Header("ETag", etag)
+
+	if status == http.StatusOK && c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(status, "image/svg+xml; charset=utf-8", []byte(svg))
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uptime-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBadges(t *testing.T) {
+	s, db := newTestServer(t)
+
+	isUp := false
+	responseTime := int64(120)
+	db.DB.Create(&models.Monitor{ID: "abc123", URL: "https://example.com", Name: "website", IsUp: &isUp, ResponseTime: &responseTime})
+	db.DB.Create(&[]models.MonitorHistory{
+		{MonitorID: "abc123", IsUp: true},
+		{MonitorID: "abc123", IsUp: true},
+		{MonitorID: "abc123", IsUp: true},
+		{MonitorID: "abc123", IsUp: false},
+	})
+
+	testCases := []struct {
+		name         string
+		path         string
+		expectedCode int
+		contains     string
+	}{
+		{"status by id", "/api/uptime-go/badge/abc123", http.StatusOK, "status: down"},
+		{"status by name", "/api/uptime-go/badge/website", http.StatusOK, "status: down"},
+		{"custom label", "/api/uptime-go/badge/website?label=api", http.StatusOK, "api: down"},
+		{"uptime", "/api/uptime-go/badge/website/uptime?window=7d", http.StatusOK, "uptime: 75.00%"},
+		{"response time of down site", "/api/uptime-go/badge/website/response-time", http.StatusOK, "response time: n/a"},
+		{"unknown monitor", "/api/uptime-go/badge/missing", http.StatusNotFound, "status: not found"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response := serve(s, http.MethodGet, tc.path)
+			assert.Equal(t, tc.expectedCode, response.Code)
+			assert.Equal(t, "image/svg+xml; charset=utf-8", response.Header().Get("Content-Type"))
+			assert.Contains(t, response.Header().Get("Cache-Control"), "max-age=60")
+			assert.Contains(t, response.Body.String(), tc.contains)
+		})
+	}
+
+	t.Run("not modified", func(t *testing.T) {
+		etag := serve(s, http.MethodGet, "/api/uptime-go/badge/website").Header().Get("ETag")
+
+		request := httptest.NewRequest(http.MethodGet, "/api/uptime-go/badge/website", nil)
+		request.Header.Set("If-None-Match", etag)
+		recorder := httptest.NewRecorder()
+		s.router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusNotModified, recorder.Code)
+	})
+}
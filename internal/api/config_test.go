@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"uptime-go/internal/configuration"
+	"uptime-go/internal/helper"
+	"uptime-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withRunningConfig(t *testing.T, config configuration.AppConfig) {
+	previous := configuration.Config
+	configuration.Config = config
+	t.Cleanup(func() { configuration.Config = previous })
+}
+
+func post(s *Server, path string, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	s.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func runningConfig() configuration.AppConfig {
+	var config configuration.AppConfig
+	config.Agent.MasterHost = "https://master.example.com"
+	config.Agent.Auth.Token = "secret"
+
+	certificateExpiredBefore := 31 * 24 * time.Hour
+	for _, url := range []string{"https://a.example.com", "https://b.example.com", "https://c.example.com"} {
+		config.Monitor = append(config.Monitor, &models.Monitor{
+			ID:                       helper.MonitorID(url),
+			URL:                      url,
+			Enabled:                  true,
+			Interval:                 time.Minute,
+			ResponseTimeThreshold:    10 * time.Second,
+			CertificateExpiredBefore: &certificateExpiredBefore,
+		})
+	}
+
+	return config
+}
+
+func TestGetConfig(t *testing.T) {
+	s, _ := newTestServer(t)
+	withRunningConfig(t, runningConfig())
+
+	response := serve(s, http.MethodGet, "/api/uptime-go/config")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.NotContains(t, response.Body.String(), "secret")
+
+	var body configuration.EffectiveConfig
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, configuration.Redacted, body.Agent.Token)
+	assert.Len(t, body.Monitor, 3)
+	assert.Equal(t, "1m", body.Monitor[0].Interval)
+	assert.Equal(t, "31d", body.Monitor[0].CertificateExpiredBefore)
+}
+
+func TestValidateConfig(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	testCases := []struct {
+		name           string
+		body           string
+		expectedCode   int
+		expectedFields []string
+	}{
+		{"valid", `{"monitor":[{"url":"https://a.example.com","interval":"1m","response_time_threshold":"10s"}]}`, http.StatusOK, nil},
+		{"duplicate url", `{"monitor":[{"url":"https://a.example.com/","interval":"1m","response_time_threshold":"10s"},{"url":"a.example.com","interval":"1m","response_time_threshold":"10s"}]}`, http.StatusBadRequest, []string{"monitor[1].url"}},
+		{"timeout above interval", `{"monitor":[{"url":"https://a.example.com","interval":"30s","response_time_threshold":"1m"}]}`, http.StatusBadRequest, []string{"monitor[0].response_time_threshold"}},
+		{"unknown dependency", `{"monitor":[{"url":"https://a.example.com","interval":"1m","response_time_threshold":"10s","depends_on":["https://b.example.com"]}]}`, http.StatusBadRequest, []string{"monitor[0].depends_on[0]"}},
+		{"invalid maintenance", `{"monitor":[{"url":"https://a.example.com","interval":"1m","response_time_threshold":"10s"}],"maintenance":[{"name":"deploy"}]}`, http.StatusBadRequest, []string{"maintenance[0]"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response := post(s, "/api/uptime-go/config/validate", tc.body)
+			assert.Equal(t, tc.expectedCode, response.Code)
+
+			var body ErrorResponse
+			assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+
+			var fields []string
+			for _, fieldError := range body.Errors {
+				fields = append(fields, fieldError.Field)
+			}
+			assert.Equal(t, tc.expectedFields, fields)
+		})
+	}
+}
+
+func TestDiffConfig(t *testing.T) {
+	s, _ := newTestServer(t)
+	withRunningConfig(t, runningConfig())
+
+	body := `{"monitor":[
+		{"url":"https://a.example.com","enabled":true,"interval":"60s","response_time_threshold":"10s"},
+		{"url":"https://b.example.com","enabled":true,"interval":"5m","response_time_threshold":"10s"},
+		{"url":"https://d.example.com","enabled":true}
+	]}`
+	response := post(s, "/api/uptime-go/config/diff", body)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var diff configuration.ConfigDiff
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &diff))
+	assert.Equal(t, 1, diff.Unchanged)
+	assert.Len(t, diff.Added, 1)
+	assert.Equal(t, "https://d.example.com", diff.Added[0].URL)
+	assert.Len(t, diff.Removed, 1)
+	assert.Equal(t, "https://c.example.com", diff.Removed[0].URL)
+	assert.Equal(t, []configuration.MonitorChange{{
+		ID:      helper.MonitorID("https://b.example.com"),
+		URL:     "https://b.example.com",
+		Changes: []configuration.FieldChange{{Field: "interval", From: "1m", To: "5m"}},
+	}}, diff.Changed)
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"uptime-go/internal/events"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// Interval of the comments sent to keep idle connections open through proxies
+const eventHeartbeatInterval = 15 * time.Second
+
+type EventStreamQueryParams struct {
+	Type    string `form:"type"`    // event type or prefix, e.g. "check" or "incident"
+	Monitor string `form:"monitor"` // monitor ID, URL or name
+}
+
+func (q EventStreamQueryParams) matches(event events.Event) bool {
+	if q.Type != "" && !strings.HasPrefix(event.Type, q.Type) {
+		return false
+	}
+
+	if q.Monitor == "" {
+		return true
+	}
+
+	switch data := event.Data.(type) {
+	case events.Check:
+		return data.MonitorID == q.Monitor || data.URL == q.Monitor || data.Name == q.Monitor
+	case events.IncidentChange:
+		monitor := data.Incident.Monitor
+		return monitor.ID == q.Monitor || monitor.URL == q.Monitor || monitor.Name == q.Monitor
+	}
+
+	return false
+}
+
+// EventStreamHandler streams check results and incident changes as
+// Server-Sent Events until the client disconnects.
+func (s *Server) EventStreamHandler(c *gin.Context) {
+	var query EventStreamQueryParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindingError(c, "Invalid query parameters", err)
+		return
+	}
+
+	// The server write timeout would otherwise close the stream
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Debug().Err(err).Msg("failed to clear write deadline for event stream")
+	}
+
+	ch, unsubscribe := s.events.Subscribe()
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(eventHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprint(c.Writer, ": connected\n\n")
+	c.Writer.Flush()
+
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			if !query.matches(event) {
+				continue
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Error().Err(err).Str("type", event.Type).Msg("failed to encode event")
+				continue
+			}
+
+			fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+			c.Writer.Flush()
+		case <-c.Request.Context().Done():
+			return
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"uptime-go/internal/events"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventStream(t *testing.T) {
+	s, db := newTestServer(t)
+	server := httptest.NewServer(s.router)
+	defer server.Close()
+
+	db.DB.Create(&models.Monitor{ID: "abc123", URL: "https://example.com"})
+	db.DB.Create(&models.Incident{ID: "outage", MonitorID: "abc123", Type: incident.Timeout})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/uptime-go/events?monitor=https://example.com", nil)
+	response, err := http.DefaultClient.Do(request)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer response.Body.Close()
+
+	assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(response.Body)
+	readEvent := func() string {
+		var lines []string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil || line == "\n" {
+				return strings.Join(lines, "")
+			}
+			lines = append(lines, line)
+		}
+	}
+	assert.Equal(t, ": connected\n", readEvent())
+
+	s.events.Publish(events.CheckCompleted, events.Check{URL: "https://other.example.com"})
+	s.events.Publish(events.CheckCompleted, events.Check{URL: "https://example.com", Status: "UP", IsUp: true})
+	event := readEvent()
+	assert.Contains(t, event, "event: check\n")
+	assert.Contains(t, event, `"url":"https://example.com"`)
+
+	acknowledged := serve(s, http.MethodPost, "/api/uptime-go/incidents/outage/acknowledge")
+	assert.Equal(t, http.StatusOK, acknowledged.Code)
+	event = readEvent()
+	assert.Contains(t, event, "event: incident.updated\n")
+	assert.Contains(t, event, `"event":"acknowledged"`)
+}
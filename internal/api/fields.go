@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondWithFields responds with data restricted to the comma-separated
+// top-level JSON fields, applied to each element when data is a list.
+func respondWithFields(c *gin.Context, data any, fields string) {
+	if fields == "" {
+		c.JSON(http.StatusOK, data)
+		return
+	}
+
+	selected, err := selectFields(data, strings.Split(fields, ","))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to select fields", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, selected)
+}
+
+func selectFields(data any, fields []string) (any, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := func(object map[string]json.RawMessage) map[string]json.RawMessage {
+		result := make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := object[strings.TrimSpace(field)]; ok {
+				result[strings.TrimSpace(field)] = value
+			}
+		}
+		return result
+	}
+
+	var list []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &list); err == nil {
+		for i := range list {
+			list[i] = keep(list[i])
+		}
+		return list, nil
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+
+	return keep(object), nil
+}
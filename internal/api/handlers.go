@@ -1,26 +1,49 @@
 package api
 
 import (
-	"io"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 	"uptime-go/internal/configuration"
+	"uptime-go/internal/helper"
+	"uptime-go/internal/models"
+	"uptime-go/internal/net/database"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type ReportQueryParams struct {
-	URL   string `form:"url"`
-	Limit int    `form:"limit"`
+	ID                 string     `form:"id"`
+	URL                string     `form:"url"`
+	Limit              int        `form:"limit" binding:"omitempty,min=1"`
+	Cursor             string     `form:"cursor"`
+	From               *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To                 *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Status             string     `form:"status" binding:"omitempty,oneof=up down degraded"`
+	Group              string     `form:"group"`
+	Tag                string     `form:"tag"`
+	Label              []string   `form:"label"`
+	Fields             string     `form:"fields"`
+	UptimePeriod       string     `form:"uptime_period" binding:"omitempty,duration"`
+	IncludeMaintenance bool       `form:"include_maintenance"`
 }
 
 func (s *Server) UpdateConfigHandler(c *gin.Context) {
-	body, err := io.ReadAll(c.Request.Body)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Failed to read request body", "error": err.Error()})
+	var body configuration.UpdateConfigRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondBindingError(c, "Invalid configuration", err)
 		return
 	}
 
 	if err := configuration.UpdateConfig(s.configPath, body); err != nil {
+		var invalid *configuration.InvalidConfigError
+		if errors.As(err, &invalid) {
+			respondConfigProblems(c, invalid.Problems)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update configuration", "error": err.Error()})
 		return
 	}
@@ -28,11 +51,47 @@ func (s *Server) UpdateConfigHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Configuration updated successfully. Please restart the application to apply changes."})
 }
 
+// GetConfigHandler returns the running configuration with secrets redacted.
+func (s *Server) GetConfigHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, configuration.Effective())
+}
+
+// ValidateConfigHandler validates a configuration without writing it.
+func (s *Server) ValidateConfigHandler(c *gin.Context) {
+	var body configuration.UpdateConfigRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondBindingError(c, "Invalid configuration", err)
+		return
+	}
+
+	if problems := body.Validate(); len(problems) > 0 {
+		respondConfigProblems(c, problems)
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Configuration is valid"})
+}
+
+// DiffConfigHandler previews which monitors a configuration would add,
+// remove or change compared to the running set.
+func (s *Server) DiffConfigHandler(c *gin.Context) {
+	var body configuration.UpdateConfigRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondBindingError(c, "Invalid configuration", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, body.Diff(configuration.Config.Monitor))
+}
+
+// GetMonitoringReport returns every monitor, or a single monitor with its
+// histories when id or url is set. Both lists are paginated with an opaque cursor,
+// the cursor of the next page is sent in the X-Next-Cursor header.
 func (s *Server) GetMonitoringReport(c *gin.Context) {
 	var queryParams ReportQueryParams
 
 	if err := c.ShouldBindQuery(&queryParams); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid query parameters", "error": err.Error()})
+		respondBindingError(c, "Invalid query parameters", err)
 		return
 	}
 
@@ -40,28 +99,138 @@ func (s *Server) GetMonitoringReport(c *gin.Context) {
 		queryParams.Limit = 1000
 	}
 
-	if queryParams.URL == "" {
-		monitors, err := s.db.GetAllMonitors()
+	var cursor *database.Cursor
+	if queryParams.Cursor != "" {
+		var err error
+		if cursor, err = database.DecodeCursor(queryParams.Cursor); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid query parameters", "error": err.Error()})
+			return
+		}
+	}
+
+	labels := map[string]string{}
+	for _, label := range queryParams.Label {
+		name, value, ok := strings.Cut(label, "=")
+		if !ok || name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid query parameters", "error": fmt.Sprintf("invalid label %q, expected name=value", label)})
+			return
+		}
+		labels[name] = value
+	}
+
+	if queryParams.ID == "" && queryParams.URL == "" {
+		monitors, err := s.db.ListMonitors(database.MonitorFilter{
+			Status: queryParams.Status,
+			Group:  queryParams.Group,
+			Tag:    queryParams.Tag,
+			Labels: labels,
+			After:  cursor,
+			Limit:  queryParams.Limit,
+		})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to retrieve monitors", "error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusOK, monitors)
+
+		if len(monitors) == queryParams.Limit {
+			setNextCursor(c, database.MonitorCursor(monitors[len(monitors)-1]))
+		}
+		respondWithFields(c, monitors, queryParams.Fields)
 		return
 	}
 
-	monitor, err := s.db.GetMonitorWithHistories(queryParams.URL, queryParams.Limit)
+	idOrURL := queryParams.ID
+	if idOrURL == "" {
+		idOrURL = helper.NormalizeURL(queryParams.URL)
+	}
+
+	monitor, err := s.db.GetMonitorWithHistories(idOrURL, database.HistoryFilter{
+		From:  queryParams.From,
+		To:    queryParams.To,
+		After: cursor,
+		Limit: queryParams.Limit,
+	})
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": "Record not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to retrieve monitor details", "error": err.Error()})
 		return
 	}
 
-	if monitor == nil {
-		c.JSON(http.StatusNotFound, gin.H{"message": "Record not found"})
+	period := helper.ParseDuration(queryParams.UptimePeriod, "30d")
+	uptime, err := s.db.GetUptime(monitor.ID, time.Now().Add(-period), !queryParams.IncludeMaintenance)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to calculate uptime", "error": err.Error()})
+		return
+	}
+	monitor.Uptime = uptime
+
+	if len(monitor.Histories) == queryParams.Limit {
+		last := monitor.Histories[len(monitor.Histories)-1]
+		setNextCursor(c, database.Cursor{Time: last.CreatedAt, Key: last.ID})
+	}
+	respondWithFields(c, monitor, queryParams.Fields)
+}
+
+func setNextCursor(c *gin.Context, cursor database.Cursor) {
+	encoded := cursor.Encode()
+
+	next := *c.Request.URL
+	query := next.Query()
+	query.Set("cursor", encoded)
+	next.RawQuery = query.Encode()
+
+	c.Header("X-Next-Cursor", encoded)
+	c.Header("Link", fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI()))
+}
+
+func (s *Server) GetMaintenanceHandler(c *gin.Context) {
+	windows, err := s.db.GetAllMaintenance()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to retrieve maintenance windows", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, windows)
+}
+
+func (s *Server) CreateMaintenanceHandler(c *gin.Context) {
+	var body configuration.MaintenanceConfig
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondBindingError(c, "Invalid request body", err)
+		return
+	}
+
+	window, err := body.Parse()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid maintenance window", "error": err.Error()})
+		return
+	}
+
+	window.Source = models.MaintenanceSourceAPI
+	if err := s.db.Upsert(window); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to save maintenance window", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, window)
+}
+
+func (s *Server) DeleteMaintenanceHandler(c *gin.Context) {
+	if err := s.db.DeleteMaintenance(c.Param("id")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": "Record not found"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to delete maintenance window", "error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, monitor)
+	c.JSON(http.StatusOK, gin.H{"message": "Maintenance window deleted"})
 }
 
 func (s *Server) HealthCheckHandler(c *gin.Context) {
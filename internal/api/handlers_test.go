@@ -0,0 +1,139 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+	"uptime-go/internal/models"
+	"uptime-go/internal/net/database"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMonitoringReport(t *testing.T) {
+	s, db := newTestServer(t)
+
+	isUp, isDown := true, false
+	db.DB.Create(&models.Monitor{ID: "a", URL: "https://a.example.com", Group: "frontend", IsUp: &isUp,
+		Tags: []string{"web", "critical"}, Labels: map[string]string{"team": "web", "env": "prod"}})
+	db.DB.Create(&models.Monitor{ID: "b", URL: "https://b.example.com", Group: "frontend", IsUp: &isDown,
+		Tags: []string{"web"}, Labels: map[string]string{"team": "web", "env": "staging"}})
+	db.DB.Create(&models.Monitor{ID: "c", URL: "https://c.example.com", IsUp: &isUp})
+
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 5; i++ {
+		db.DB.Create(&models.MonitorHistory{MonitorID: "a", IsUp: true, CreatedAt: start.Add(time.Duration(i) * time.Minute)})
+	}
+
+	urls := func(path string) ([]string, string) {
+		response := serve(s, http.MethodGet, path)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		var monitors []models.Monitor
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &monitors))
+
+		var result []string
+		for _, monitor := range monitors {
+			result = append(result, monitor.URL)
+		}
+		return result, response.Header().Get("X-Next-Cursor")
+	}
+
+	t.Run("filters", func(t *testing.T) {
+		result, _ := urls("/api/uptime-go/reports?group=frontend")
+		assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, result)
+
+		result, _ = urls("/api/uptime-go/reports?status=up")
+		assert.Equal(t, []string{"https://a.example.com", "https://c.example.com"}, result)
+
+		result, _ = urls("/api/uptime-go/reports?tag=web")
+		assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, result)
+
+		result, _ = urls("/api/uptime-go/reports?tag=critical")
+		assert.Equal(t, []string{"https://a.example.com"}, result)
+
+		result, _ = urls("/api/uptime-go/reports?label=team=web&label=env=staging")
+		assert.Equal(t, []string{"https://b.example.com"}, result)
+
+		result, _ = urls("/api/uptime-go/reports?label=env=dev")
+		assert.Empty(t, result)
+	})
+
+	t.Run("monitor pagination", func(t *testing.T) {
+		result, cursor := urls("/api/uptime-go/reports?limit=2")
+		assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, result)
+		assert.NotEmpty(t, cursor)
+
+		result, cursor = urls("/api/uptime-go/reports?limit=2&cursor=" + cursor)
+		assert.Equal(t, []string{"https://c.example.com"}, result)
+		assert.Empty(t, cursor)
+	})
+
+	t.Run("history pagination", func(t *testing.T) {
+		var seen []time.Time
+		path := "/api/uptime-go/reports?url=https://a.example.com&limit=2"
+		for path != "" {
+			response := serve(s, http.MethodGet, path)
+			assert.Equal(t, http.StatusOK, response.Code)
+
+			var monitor models.Monitor
+			assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &monitor))
+			for _, history := range monitor.Histories {
+				seen = append(seen, history.CreatedAt)
+			}
+
+			path = ""
+			if cursor := response.Header().Get("X-Next-Cursor"); cursor != "" {
+				path = "/api/uptime-go/reports?url=https://a.example.com&limit=2&cursor=" + cursor
+			}
+		}
+
+		assert.Len(t, seen, 5)
+		for i := 1; i < len(seen); i++ {
+			assert.True(t, seen[i].Before(seen[i-1]))
+		}
+	})
+
+	t.Run("time range", func(t *testing.T) {
+		from := start.Add(90 * time.Second).UTC().Format(time.RFC3339)
+		response := serve(s, http.MethodGet, "/api/uptime-go/reports?url=https://a.example.com&from="+from)
+
+		var monitor models.Monitor
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &monitor))
+		assert.Len(t, monitor.Histories, 3)
+	})
+
+	t.Run("by id", func(t *testing.T) {
+		db.DB.Create(&models.Monitor{ID: "a-eu", URL: "https://a.example.com", IsUp: &isDown})
+
+		response := serve(s, http.MethodGet, "/api/uptime-go/reports?id=a-eu")
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		var monitor models.Monitor
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &monitor))
+		assert.Equal(t, "a-eu", monitor.ID)
+		assert.Empty(t, monitor.Histories)
+
+		result, cursor := urls("/api/uptime-go/reports?limit=1&cursor=" + database.MonitorCursor(models.Monitor{ID: "a", URL: "https://a.example.com"}).Encode())
+		assert.Equal(t, []string{"https://a.example.com"}, result)
+		assert.NotEmpty(t, cursor)
+
+		db.DB.Delete(&models.Monitor{ID: "a-eu"})
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		response := serve(s, http.MethodGet, "/api/uptime-go/reports?url=https://a.example.com&fields=url,is_up")
+
+		var body map[string]any
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+		assert.Equal(t, map[string]any{"url": "https://a.example.com", "is_up": true}, body)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, serve(s, http.MethodGet, "/api/uptime-go/reports?url=https://missing.example.com").Code)
+		assert.Equal(t, http.StatusBadRequest, serve(s, http.MethodGet, "/api/uptime-go/reports?cursor=invalid").Code)
+		assert.Equal(t, http.StatusBadRequest, serve(s, http.MethodGet, "/api/uptime-go/reports?status=unknown").Code)
+		assert.Equal(t, http.StatusBadRequest, serve(s, http.MethodGet, "/api/uptime-go/reports?label=team").Code)
+	})
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"uptime-go/internal/events"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+	"uptime-go/internal/monitor"
+	"uptime-go/internal/net/database"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type IncidentActionRequest struct {
+	By   string `json:"by"`
+	Note string `json:"note"`
+}
+
+type IncidentNoteRequest struct {
+	By   string `json:"by"`
+	Note string `json:"note" binding:"required"`
+}
+
+type IncidentListQueryParams struct {
+	Monitor string     `form:"monitor"`
+	Type    string     `form:"type"`
+	State   string     `form:"state" binding:"omitempty,oneof=open resolved"`
+	From    *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To      *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort    string     `form:"sort"`
+	Limit   int        `form:"limit" binding:"omitempty,min=1,max=1000"`
+	Offset  int        `form:"offset" binding:"omitempty,min=0"`
+}
+
+type IncidentListResponse struct {
+	Data   []models.Incident `json:"data"`
+	Total  int64             `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+const apiActor = "api"
+
+func (s *Server) ListIncidentsHandler(c *gin.Context) {
+	var query IncidentListQueryParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindingError(c, "Invalid query parameters", err)
+		return
+	}
+
+	if query.Limit == 0 {
+		query.Limit = 50
+	}
+
+	incidents, total, err := s.db.ListIncidents(database.IncidentFilter{
+		Monitor: query.Monitor,
+		Type:    incident.Type(query.Type),
+		State:   query.State,
+		From:    query.From,
+		To:      query.To,
+		Sort:    query.Sort,
+		Limit:   query.Limit,
+		Offset:  query.Offset,
+	})
+	if err != nil {
+		if errors.Is(err, database.ErrInvalidSort) {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid query parameters", "error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to retrieve incidents", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, IncidentListResponse{
+		Data:   incidents,
+		Total:  total,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	})
+}
+
+func (s *Server) GetIncidentHandler(c *gin.Context) {
+	inc, err := s.db.GetIncident(c.Param("id"))
+	if err != nil {
+		respondIncidentError(c, err, "Failed to retrieve incident")
+		return
+	}
+
+	c.JSON(http.StatusOK, inc)
+}
+
+func (s *Server) AcknowledgeIncidentHandler(c *gin.Context) {
+	var body IncidentActionRequest
+	if !bindIncidentAction(c, &body) {
+		return
+	}
+
+	inc, err := s.db.AcknowledgeIncident(c.Param("id"), actorOrDefault(body.By), body.Note)
+	if err != nil {
+		respondIncidentError(c, err, "Failed to acknowledge incident")
+		return
+	}
+
+	monitor.SyncIncidentStatus(s.db, inc, incident.OnInvestigation, actorOrDefault(body.By))
+	s.publishIncidentChange(inc)
+	c.JSON(http.StatusOK, inc)
+}
+
+func (s *Server) AddIncidentNoteHandler(c *gin.Context) {
+	var body IncidentNoteRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondBindingError(c, "Invalid request body", err)
+		return
+	}
+
+	inc, err := s.db.AddIncidentNote(c.Param("id"), actorOrDefault(body.By), body.Note)
+	if err != nil {
+		respondIncidentError(c, err, "Failed to add note")
+		return
+	}
+
+	s.publishIncidentChange(inc)
+	c.JSON(http.StatusCreated, inc)
+}
+
+func (s *Server) MarkFalsePositiveHandler(c *gin.Context) {
+	var body IncidentActionRequest
+	if !bindIncidentAction(c, &body) {
+		return
+	}
+
+	inc, err := s.db.MarkIncidentFalsePositive(c.Param("id"), actorOrDefault(body.By), body.Note)
+	if err != nil {
+		respondIncidentError(c, err, "Failed to mark incident as false positive")
+		return
+	}
+
+	monitor.SyncIncidentStatus(s.db, inc, incident.FalsePositive, actorOrDefault(body.By))
+	s.publishIncidentChange(inc)
+	c.JSON(http.StatusOK, inc)
+}
+
+// publishIncidentChange publishes the latest timeline event of an incident.
+func (s *Server) publishIncidentChange(inc *models.Incident) {
+	if len(inc.Events) == 0 {
+		return
+	}
+
+	last := inc.Events[len(inc.Events)-1]
+	s.events.Publish(events.IncidentEventType(last.Type), events.IncidentChange{
+		Incident: inc,
+		Event:    last.Type,
+		Message:  last.Message,
+		Actor:    last.Actor,
+	})
+}
+
+// bindIncidentAction decodes an optional action body. An empty body is valid.
+func bindIncidentAction(c *gin.Context, body *IncidentActionRequest) bool {
+	if c.Request.ContentLength == 0 {
+		return true
+	}
+
+	if err := c.ShouldBindJSON(body); err != nil {
+		respondBindingError(c, "Invalid request body", err)
+		return false
+	}
+
+	return true
+}
+
+func respondIncidentError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Record not found"})
+	case errors.Is(err, database.ErrIncidentSolved):
+		c.JSON(http.StatusConflict, gin.H{"message": message, "error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": message, "error": err.Error()})
+	}
+}
+
+func actorOrDefault(actor string) string {
+	if actor == "" {
+		return apiActor
+	}
+
+	return actor
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uptime-go/internal/configuration"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListIncidents(t *testing.T) {
+	s, db := newTestServer(t)
+
+	db.DB.Create(&models.Monitor{ID: "abc123", URL: "https://example.com"})
+	db.DB.Create(&models.Incident{ID: "outage", MonitorID: "abc123", Type: incident.Timeout, IncidentID: 42})
+
+	testCases := []struct {
+		name         string
+		path         string
+		expectedCode int
+		expectedLen  int
+	}{
+		{"all", "/api/uptime-go/incidents", http.StatusOK, 1},
+		{"filtered", "/api/uptime-go/incidents?monitor=https://example.com&state=open&from=2020-01-01T00:00:00Z", http.StatusOK, 1},
+		{"no match", "/api/uptime-go/incidents?type=dns_failure", http.StatusOK, 0},
+		{"invalid state", "/api/uptime-go/incidents?state=closed", http.StatusBadRequest, 0},
+		{"invalid sort", "/api/uptime-go/incidents?sort=description", http.StatusBadRequest, 0},
+		{"invalid time", "/api/uptime-go/incidents?from=yesterday", http.StatusBadRequest, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response := serve(s, http.MethodGet, tc.path)
+			assert.Equal(t, tc.expectedCode, response.Code)
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Data  []models.Incident `json:"data"`
+				Total int64             `json:"total"`
+			}
+			assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+			assert.Len(t, body.Data, tc.expectedLen)
+			assert.Equal(t, int64(tc.expectedLen), body.Total)
+			if tc.expectedLen > 0 {
+				assert.Equal(t, uint64(42), body.Data[0].IncidentID)
+				assert.NotNil(t, body.Data[0].Duration)
+			}
+		})
+	}
+}
+
+func TestAcknowledgeIncidentStandalone(t *testing.T) {
+	var received []map[string]any
+	notifier := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		received = append(received, body)
+	}))
+	defer notifier.Close()
+
+	previous := configuration.Config
+	t.Cleanup(func() { configuration.Config = previous })
+	configuration.Config.Standalone = true
+	configuration.Config.Notifiers = []configuration.NotifierConfig{{Name: "ops", URL: notifier.URL}}
+
+	s, db := newTestServer(t)
+	db.DB.Create(&models.Monitor{ID: "abc123", URL: "https://example.com"})
+	db.DB.Create(&models.Incident{ID: "outage", MonitorID: "abc123", Type: incident.Timeout})
+
+	response := post(s, "/api/uptime-go/incidents/outage/acknowledge", `{"by":"alice"}`)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	// Standalone incidents have no master ID but still reach the notifiers
+	require.Len(t, received, 1)
+	assert.Equal(t, "outage", received[0]["incident_id"])
+	assert.Equal(t, string(incident.OnInvestigation), received[0]["status"])
+
+	inc, err := db.GetIncident("outage")
+	require.NoError(t, err)
+	last := inc.Events[len(inc.Events)-1]
+	assert.Equal(t, models.IncidentEventStatusSynced, last.Type)
+	assert.Equal(t, "alice", last.Actor)
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"uptime-go/internal/monitor"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// CheckMonitorHandler runs an immediate check of a monitor by ID or name
+// through the running monitor, so the result and any incident transition are
+// recorded like a scheduled check.
+func (s *Server) CheckMonitorHandler(c *gin.Context) {
+	if s.monitor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"message": "Monitoring is not running"})
+		return
+	}
+
+	// The check is bounded by the monitor timeout, which may exceed the
+	// server write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Debug().Err(err).Msg("failed to clear write deadline for check")
+	}
+
+	check, err := s.monitor.CheckNow(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, monitor.ErrMonitorNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": "Record not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to check monitor", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, check)
+}
@@ -0,0 +1,15 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckMonitorWithoutMonitoring(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	response := serve(s, http.MethodPost, "/api/uptime-go/monitors/abc123/check")
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+}
@@ -0,0 +1,301 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"uptime-go/internal/configuration"
+	"uptime-go/internal/events"
+	"uptime-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// routeDoc describes a route in the OpenAPI document. Query and Body are
+// structs with form and json tags, Response is the type of the body returned
+// with Status, or nil when the route returns ContentType.
+type routeDoc struct {
+	Summary     string
+	Tag         string
+	Query       any
+	Body        any
+	Status      int
+	Response    any
+	ContentType string
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+type HealthResponse struct {
+	Status  string `json:"status"`
+	Service string `json:"service"`
+}
+
+// routeDocs documents every route registered in setupRoutes, keyed by method
+// and gin path.
+var routeDocs = map[string]routeDoc{
+	"GET /health":                     {Summary: "Health check", Tag: "service", Response: HealthResponse{}},
+	"GET /api/uptime-go/openapi.json": {Summary: "This OpenAPI document", Tag: "service", ContentType: "application/json"},
+
+	"GET /status":           {Summary: "Public status page", Tag: "status", ContentType: "text/html"},
+	"GET /status/feed.json": {Summary: "Incidents of public monitors as a JSON Feed", Tag: "status", ContentType: "application/feed+json"},
+	"GET /status/feed.atom": {Summary: "Incidents of public monitors as an Atom feed", Tag: "status", ContentType: "application/atom+xml"},
+
+	"GET /api/uptime-go/config":           {Summary: "Running configuration with secrets redacted", Tag: "config", Response: configuration.EffectiveConfig{}},
+	"POST /api/uptime-go/config":          {Summary: "Replace the monitoring configuration", Tag: "config", Body: configuration.UpdateConfigRequest{}, Response: MessageResponse{}},
+	"POST /api/uptime-go/config/validate": {Summary: "Validate a configuration without writing it", Tag: "config", Body: configuration.UpdateConfigRequest{}, Response: MessageResponse{}},
+	"POST /api/uptime-go/config/diff":     {Summary: "Preview monitors added, removed or changed by a configuration", Tag: "config", Body: configuration.UpdateConfigRequest{}, Response: configuration.ConfigDiff{}},
+
+	"GET /api/uptime-go/events":              {Summary: "Stream check results and incident changes as Server-Sent Events", Tag: "monitors", Query: EventStreamQueryParams{}, ContentType: "text/event-stream"},
+	"GET /api/uptime-go/reports":             {Summary: "Report every monitor, or a single monitor with its histories when url is set", Tag: "monitors", Query: ReportQueryParams{}, Response: []models.Monitor{}},
+	"POST /api/uptime-go/monitors/:id/check": {Summary: "Check a monitor immediately", Tag: "monitors", Response: events.Check{}},
+
+	"GET /api/uptime-go/badge/:monitor":               {Summary: "Status badge", Tag: "badges", Query: BadgeQueryParams{}, ContentType: "image/svg+xml"},
+	"GET /api/uptime-go/badge/:monitor/uptime":        {Summary: "Uptime percentage badge", Tag: "badges", Query: BadgeQueryParams{}, ContentType: "image/svg+xml"},
+	"GET /api/uptime-go/badge/:monitor/response-time": {Summary: "Last response time badge", Tag: "badges", Query: BadgeQueryParams{}, ContentType: "image/svg+xml"},
+
+	"GET /api/uptime-go/maintenance":        {Summary: "List maintenance windows", Tag: "maintenance", Response: []models.Maintenance{}},
+	"POST /api/uptime-go/maintenance":       {Summary: "Create a maintenance window", Tag: "maintenance", Body: configuration.MaintenanceConfig{}, Status: http.StatusCreated, Response: models.Maintenance{}},
+	"DELETE /api/uptime-go/maintenance/:id": {Summary: "Delete a maintenance window", Tag: "maintenance", Response: MessageResponse{}},
+
+	"GET /api/uptime-go/incidents":                     {Summary: "List incidents", Tag: "incidents", Query: IncidentListQueryParams{}, Response: IncidentListResponse{}},
+	"GET /api/uptime-go/incidents/:id":                 {Summary: "Get an incident with its timeline", Tag: "incidents", Response: models.Incident{}},
+	"POST /api/uptime-go/incidents/:id/acknowledge":    {Summary: "Acknowledge an incident", Tag: "incidents", Body: IncidentActionRequest{}, Response: models.Incident{}},
+	"POST /api/uptime-go/incidents/:id/notes":          {Summary: "Add a note to an incident", Tag: "incidents", Body: IncidentNoteRequest{}, Status: http.StatusCreated, Response: models.Incident{}},
+	"POST /api/uptime-go/incidents/:id/false-positive": {Summary: "Close an incident as a false positive", Tag: "incidents", Body: IncidentActionRequest{}, Response: models.Incident{}},
+}
+
+var pathParamPattern = regexp.MustCompile(`:(\w+)`)
+
+func (s *Server) OpenAPIHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, s.openAPIDocument())
+}
+
+// openAPIDocument builds an OpenAPI 3 document from the registered routes,
+// deriving schemas from the request and response types.
+func (s *Server) openAPIDocument() map[string]any {
+	generator := &schemaGenerator{schemas: map[string]any{}}
+	paths := map[string]map[string]any{}
+
+	for _, route := range s.router.Routes() {
+		doc, ok := routeDocs[route.Method+" "+route.Path]
+		if !ok {
+			continue
+		}
+
+		path := pathParamPattern.ReplaceAllString(route.Path, "{$1}")
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		paths[path][strings.ToLower(route.Method)] = generator.operation(route.Path, doc)
+	}
+
+	generator.schemas["ErrorResponse"] = generator.structSchema(reflect.TypeOf(ErrorResponse{}))
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "uptime-go",
+			"version": "1.0.0",
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": generator.schemas},
+	}
+}
+
+type schemaGenerator struct {
+	schemas map[string]any
+}
+
+func (g *schemaGenerator) operation(path string, doc routeDoc) map[string]any {
+	var parameters []any
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		parameters = append(parameters, map[string]any{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]any{"type": "string"},
+		})
+	}
+
+	if doc.Query != nil {
+		queryType := reflect.TypeOf(doc.Query)
+		for i := 0; i < queryType.NumField(); i++ {
+			field := queryType.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+
+			parameters = append(parameters, map[string]any{
+				"name":     name,
+				"in":       "query",
+				"required": isRequired(field),
+				"schema":   g.fieldSchema(field),
+			})
+		}
+	}
+
+	status := doc.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	response := map[string]any{"description": http.StatusText(status)}
+	switch {
+	case doc.Response != nil:
+		response["content"] = map[string]any{
+			"application/json": map[string]any{"schema": g.schema(reflect.TypeOf(doc.Response))},
+		}
+	case doc.ContentType != "":
+		response["content"] = map[string]any{doc.ContentType: map[string]any{}}
+	}
+
+	errorResponse := map[string]any{
+		"description": "Error",
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/ErrorResponse"}},
+		},
+	}
+
+	operation := map[string]any{
+		"summary": doc.Summary,
+		"tags":    []string{doc.Tag},
+		"responses": map[string]any{
+			fmt.Sprint(status): response,
+			"default":          errorResponse,
+		},
+	}
+
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+
+	if doc.Body != nil {
+		bodyType := reflect.TypeOf(doc.Body)
+		operation["requestBody"] = map[string]any{
+			// Bodies without required fields, e.g. incident actions, may be omitted
+			"required": slices.ContainsFunc(reflect.VisibleFields(bodyType), isRequired),
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": g.schema(bodyType)},
+			},
+		}
+	}
+
+	return operation
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) map[string]any {
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == reflect.TypeOf(time.Duration(0)):
+		return map[string]any{"type": "integer", "description": "Duration in nanoseconds"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.Struct:
+		if _, ok := g.schemas[t.Name()]; !ok {
+			g.schemas[t.Name()] = map[string]any{} // Placeholder for recursive types
+			g.schemas[t.Name()] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	default:
+		return map[string]any{}
+	}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = g.fieldSchema(field)
+		if isRequired(field) {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// fieldSchema returns the schema of a struct field, including constraints
+// from its binding tag.
+func (g *schemaGenerator) fieldSchema(field reflect.StructField) map[string]any {
+	schema := g.schema(field.Type)
+	if _, isRef := schema["$ref"]; isRef {
+		return schema
+	}
+
+	for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "oneof":
+			schema["enum"] = strings.Fields(param)
+		case "min", "max":
+			value, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+			key := map[string]string{"min": "minimum", "max": "maximum"}[name]
+			if schema["type"] == "array" {
+				key = map[string]string{"min": "minItems", "max": "maxItems"}[name]
+			}
+			schema[key] = value
+		case "duration":
+			schema["pattern"] = `^(\d+[smhd])+$`
+		}
+	}
+
+	return schema
+}
+
+func isRequired(field reflect.StructField) bool {
+	for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+
+	return false
+}
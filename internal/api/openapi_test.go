@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIDocumentsEveryRoute(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	for _, route := range s.router.Routes() {
+		_, ok := routeDocs[route.Method+" "+route.Path]
+		assert.True(t, ok, "route %s %s is not documented", route.Method, route.Path)
+	}
+
+	registered := map[string]bool{}
+	for _, route := range s.router.Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+	for key := range routeDocs {
+		assert.True(t, registered[key], "documented route %s is not registered", key)
+	}
+
+	response := serve(s, http.MethodGet, "/api/uptime-go/openapi.json")
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var document struct {
+		OpenAPI    string                    `json:"openapi"`
+		Paths      map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &document))
+	assert.Equal(t, "3.0.3", document.OpenAPI)
+	assert.Contains(t, document.Paths, "/api/uptime-go/incidents/{id}/acknowledge")
+	assert.Contains(t, document.Components.Schemas, "Monitor")
+	assert.Equal(t, []any{"url"}, document.Components.Schemas["MonitorConfig"]["required"])
+}
+
+func TestUpdateConfigValidation(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	body := `{"monitor":[{"url":"https://example.com","interval":"5 minutes"},{"interval":"1m"}]}`
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/api/uptime-go/config", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	s.router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	var response ErrorResponse
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.ElementsMatch(t, []FieldError{
+		{Field: "monitor[0].interval", Rule: "duration", Message: `invalid duration "5 minutes", expected e.g. 30s, 5m, 1h30m or 31d`},
+		{Field: "monitor[1].url", Rule: "required", Message: "is required"},
+	}, response.Errors)
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+	"uptime-go/internal/events"
+	"uptime-go/internal/monitor"
 	"uptime-go/internal/net/database"
 
 	"github.com/gin-gonic/gin"
@@ -12,27 +14,41 @@ import (
 )
 
 type Server struct {
-	db         *database.Database
+	db         database.Store
 	router     *gin.Engine
 	server     *http.Server
 	configPath string
+	monitor    *monitor.UptimeMonitor
+	events     *events.Bus
+	done       chan struct{} // closed on shutdown to end event streams
 }
 
 type ServerConfig struct {
 	Bind       string
 	Port       string
 	ConfigPath string
+	Monitor    *monitor.UptimeMonitor // optional, the running monitor
 }
 
-func NewServer(cfg ServerConfig, db *database.Database) *Server {
+func NewServer(cfg ServerConfig, db database.Store) *Server {
+	setupValidator()
+
 	router := gin.New()
 	router.Use(gin.Recovery())
 	router.Use(accessLogger())
 
+	bus := events.NewBus()
+	if cfg.Monitor != nil {
+		bus = cfg.Monitor.Events()
+	}
+
 	server := &Server{
 		db:         db,
 		router:     router,
 		configPath: cfg.ConfigPath,
+		monitor:    cfg.Monitor,
+		events:     bus,
+		done:       make(chan struct{}),
 		server: &http.Server{
 			Addr:         fmt.Sprintf("%s:%s", cfg.Bind, cfg.Port),
 			Handler:      router.Handler(),
@@ -42,6 +58,7 @@ func NewServer(cfg ServerConfig, db *database.Database) *Server {
 		},
 	}
 
+	server.server.RegisterOnShutdown(func() { close(server.done) })
 	server.setupRoutes()
 
 	return server
@@ -73,12 +90,43 @@ func (s *Server) Shutdown() {
 func (s *Server) setupRoutes() {
 	s.router.GET("/health", s.HealthCheckHandler)
 
+	statusGroup := s.router.Group("/status")
+	statusGroup.GET("", s.StatusPageHandler)
+	statusGroup.GET("/feed.json", s.StatusJSONFeedHandler)
+	statusGroup.GET("/feed.atom", s.StatusAtomFeedHandler)
+
 	api := s.router.Group("/api/uptime-go")
-	// api.GET("/config")
-	api.POST("/config", s.UpdateConfigHandler)
+	api.GET("/openapi.json", s.OpenAPIHandler)
+	configGroup := api.Group("/config")
+	configGroup.GET("", s.GetConfigHandler)
+	configGroup.POST("", s.UpdateConfigHandler)
+	configGroup.POST("/validate", s.ValidateConfigHandler)
+	configGroup.POST("/diff", s.DiffConfigHandler)
+
+	api.GET("/events", s.EventStreamHandler)
 
 	reportGroup := api.Group("/reports")
 	reportGroup.GET("", s.GetMonitoringReport)
+
+	monitorGroup := api.Group("/monitors")
+	monitorGroup.POST("/:id/check", s.CheckMonitorHandler)
+
+	maintenanceGroup := api.Group("/maintenance")
+	maintenanceGroup.GET("", s.GetMaintenanceHandler)
+	maintenanceGroup.POST("", s.CreateMaintenanceHandler)
+	maintenanceGroup.DELETE("/:id", s.DeleteMaintenanceHandler)
+
+	badgeGroup := api.Group("/badge")
+	badgeGroup.GET("/:monitor", s.StatusBadgeHandler)
+	badgeGroup.GET("/:monitor/uptime", s.UptimeBadgeHandler)
+	badgeGroup.GET("/:monitor/response-time", s.ResponseTimeBadgeHandler)
+
+	incidentGroup := api.Group("/incidents")
+	incidentGroup.GET("", s.ListIncidentsHandler)
+	incidentGroup.GET("/:id", s.GetIncidentHandler)
+	incidentGroup.POST("/:id/acknowledge", s.AcknowledgeIncidentHandler)
+	incidentGroup.POST("/:id/notes", s.AddIncidentNoteHandler)
+	incidentGroup.POST("/:id/false-positive", s.MarkFalsePositiveHandler)
 }
 
 func accessLogger() gin.HandlerFunc {
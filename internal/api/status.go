@@ -0,0 +1,516 @@
+package api
+
+import (
+	"embed"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+	"uptime-go/internal/configuration"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+	"uptime-go/internal/net/database"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	statusPageDays      = 90
+	statusPageIncidents = 30 * 24 * time.Hour
+	statusFeedLimit     = 50
+	maintenanceNotice   = 7 * 24 * time.Hour
+)
+
+//go:embed templates/status.html
+var templates embed.FS
+
+var statusTemplate = template.Must(template.New("status.html").Funcs(template.FuncMap{
+	"deref": func(f *float64) float64 { return *f },
+}).ParseFS(templates, "templates/status.html"))
+
+var incidentTitles = map[incident.Type]string{
+	incident.UnexpectedStatusCode:  "Service errors",
+	incident.Timeout:               "Service not responding",
+	incident.DNSFailure:            "DNS resolution failure",
+	incident.ConnectionRefused:     "Service unreachable",
+	incident.ConnectionReset:       "Connection failures",
+	incident.TLSHandshakeFailure:   "Secure connection failure",
+	incident.CertificateInvalid:    "Invalid certificate",
+	incident.SSLExpired:            "Certificate expiry",
+	incident.Flapping:              "Intermittent availability",
+	incident.Degraded:              "Degraded performance",
+	incident.PerformanceRegression: "Slower than usual",
+}
+
+type statusPage struct {
+	Title           string              `json:"title"`
+	Status          string              `json:"status"`
+	StatusText      string              `json:"status_text"`
+	GeneratedAt     time.Time           `json:"generated_at"`
+	Groups          []statusGroup       `json:"groups"`
+	ActiveIncidents []statusIncident    `json:"active_incidents"`
+	RecentIncidents []statusIncident    `json:"recent_incidents"`
+	Maintenance     []statusMaintenance `json:"maintenance"`
+	AtomURL         string              `json:"-"`
+	JSONFeedURL     string              `json:"-"`
+}
+
+type statusGroup struct {
+	Name     string          `json:"name"`
+	Monitors []statusMonitor `json:"monitors"`
+}
+
+type statusMonitor struct {
+	Name       string      `json:"name"`
+	Status     string      `json:"status"`
+	StatusText string      `json:"status_text"`
+	Uptime     *float64    `json:"uptime"`
+	Days       []statusDay `json:"days"`
+}
+
+type statusDay struct {
+	Date    string  `json:"date"`
+	Uptime  float64 `json:"uptime"`
+	HasData bool    `json:"has_data"`
+	Level   string  `json:"level"`
+}
+
+type statusIncident struct {
+	ID         string     `json:"id"`
+	Monitor    string     `json:"monitor"`
+	Title      string     `json:"title"`
+	StartedAt  time.Time  `json:"started_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	Duration   string     `json:"duration"`
+}
+
+type statusMaintenance struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schedule    string `json:"schedule"`
+	Active      bool   `json:"active"`
+}
+
+func (s *Server) StatusPageHandler(c *gin.Context) {
+	page, err := s.buildStatusPage(time.Now())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to build status page")
+		c.String(http.StatusInternalServerError, "Status page is temporarily unavailable")
+		return
+	}
+
+	page.AtomURL = "/status/feed.atom"
+	page.JSONFeedURL = "/status/feed.json"
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("Cache-Control", "public, max-age=30")
+	if err := statusTemplate.Execute(c.Writer, page); err != nil {
+		log.Error().Err(err).Msg("failed to render status page")
+	}
+}
+
+func (s *Server) StatusJSONFeedHandler(c *gin.Context) {
+	incidents, err := s.getPublicIncidents()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to retrieve incidents", "error": err.Error()})
+		return
+	}
+
+	type feedItem struct {
+		ID            string    `json:"id"`
+		URL           string    `json:"url"`
+		Title         string    `json:"title"`
+		ContentText   string    `json:"content_text"`
+		DatePublished time.Time `json:"date_published"`
+		DateModified  time.Time `json:"date_modified"`
+	}
+
+	base := baseURL(c)
+	items := []feedItem{}
+	for _, inc := range incidents {
+		items = append(items, feedItem{
+			ID:            inc.ID,
+			URL:           base + "/status",
+			Title:         inc.Monitor + ": " + inc.Title,
+			ContentText:   incidentSummary(inc),
+			DatePublished: inc.StartedAt,
+			DateModified:  incidentUpdated(inc),
+		})
+	}
+
+	c.Header("Cache-Control", "public, max-age=30")
+	c.JSON(http.StatusOK, gin.H{
+		"version":       "https://jsonfeed.org/version/1.1",
+		"title":         statusTitle() + " incidents",
+		"home_page_url": base + "/status",
+		"feed_url":      base + "/status/feed.json",
+		"items":         items,
+	})
+}
+
+func (s *Server) StatusAtomFeedHandler(c *gin.Context) {
+	incidents, err := s.getPublicIncidents()
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to retrieve incidents")
+		return
+	}
+
+	type atomLink struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr,omitempty"`
+	}
+
+	type atomEntry struct {
+		ID        string   `xml:"id"`
+		Title     string   `xml:"title"`
+		Link      atomLink `xml:"link"`
+		Published string   `xml:"published"`
+		Updated   string   `xml:"updated"`
+		Summary   string   `xml:"summary"`
+	}
+
+	type atomFeed struct {
+		XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string      `xml:"id"`
+		Title   string      `xml:"title"`
+		Updated string      `xml:"updated"`
+		Links   []atomLink  `xml:"link"`
+		Entries []atomEntry `xml:"entry"`
+	}
+
+	base := baseURL(c)
+	updated := time.Now()
+	if len(incidents) > 0 {
+		updated = incidentUpdated(incidents[0])
+	}
+
+	feed := atomFeed{
+		ID:      base + "/status",
+		Title:   statusTitle() + " incidents",
+		Updated: updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: base + "/status"},
+			{Href: base + "/status/feed.atom", Rel: "self"},
+		},
+	}
+
+	for _, inc := range incidents {
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:        "urn:uptime-go:incident:" + inc.ID,
+			Title:     inc.Monitor + ": " + inc.Title,
+			Link:      atomLink{Href: base + "/status"},
+			Published: inc.StartedAt.UTC().Format(time.RFC3339),
+			Updated:   incidentUpdated(inc).UTC().Format(time.RFC3339),
+			Summary:   incidentSummary(inc),
+		})
+	}
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to render feed")
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=30")
+	c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", append([]byte(xml.Header), body...))
+}
+
+// buildStatusPage collects everything shown on the status page. Only
+// monitors marked public are included.
+func (s *Server) buildStatusPage(now time.Time) (*statusPage, error) {
+	page := &statusPage{
+		Title:       statusTitle(),
+		GeneratedAt: now,
+	}
+
+	monitors, err := s.db.GetPublicMonitors()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(monitors))
+	for i, m := range monitors {
+		ids[i] = m.ID
+	}
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(statusPageDays - 1))
+	days, err := s.db.GetDailyUptime(ids, since)
+	if err != nil {
+		return nil, err
+	}
+
+	dailyByMonitor := map[string]map[string]database.DailyUptime{}
+	for _, day := range days {
+		if dailyByMonitor[day.MonitorID] == nil {
+			dailyByMonitor[day.MonitorID] = map[string]database.DailyUptime{}
+		}
+		dailyByMonitor[day.MonitorID][day.Day] = day
+	}
+
+	windows, err := s.db.GetAllMaintenance()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, m := range monitors {
+		status := monitorStatus(m, windows, now)
+		counts[status]++
+
+		entry := statusMonitor{
+			Name:       m.DisplayName(),
+			Status:     status,
+			StatusText: monitorStatusText(status),
+		}
+
+		var total, up int64
+		for d := since; !d.After(today); d = d.AddDate(0, 0, 1) {
+			date := d.Format(time.DateOnly)
+			day := statusDay{Date: date, Level: "none"}
+			if stat, ok := dailyByMonitor[m.ID][date]; ok && stat.Total > 0 {
+				total += stat.Total
+				up += stat.Up
+				day.HasData = true
+				day.Uptime = stat.Percentage()
+				day.Level = uptimeLevel(day.Uptime)
+			}
+			entry.Days = append(entry.Days, day)
+		}
+
+		if total > 0 {
+			uptime := float64(up) / float64(total) * 100
+			entry.Uptime = &uptime
+		}
+
+		if len(page.Groups) == 0 || page.Groups[len(page.Groups)-1].Name != m.Group {
+			page.Groups = append(page.Groups, statusGroup{Name: m.Group})
+		}
+		group := &page.Groups[len(page.Groups)-1]
+		group.Monitors = append(group.Monitors, entry)
+	}
+
+	page.Status, page.StatusText = overallStatus(counts, len(monitors))
+
+	incidents, err := s.db.GetIncidentsForMonitors(ids, now.Add(-statusPageIncidents), statusFeedLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, inc := range incidents {
+		entry := toStatusIncident(inc, now)
+		if inc.IsSolved() {
+			page.RecentIncidents = append(page.RecentIncidents, entry)
+		} else {
+			page.ActiveIncidents = append(page.ActiveIncidents, entry)
+		}
+	}
+
+	for _, window := range windows {
+		if !appliesToAny(window, monitors) {
+			continue
+		}
+
+		active := window.IsActive(now)
+		upcoming := window.StartsAt != nil && window.StartsAt.After(now) && window.StartsAt.Before(now.Add(maintenanceNotice))
+		if !active && !upcoming {
+			continue
+		}
+
+		page.Maintenance = append(page.Maintenance, statusMaintenance{
+			Name:        window.Name,
+			Description: window.Description,
+			Schedule:    maintenanceSchedule(window),
+			Active:      active,
+		})
+	}
+
+	return page, nil
+}
+
+func (s *Server) getPublicIncidents() ([]statusIncident, error) {
+	monitors, err := s.db.GetPublicMonitors()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(monitors))
+	for i, m := range monitors {
+		ids[i] = m.ID
+	}
+
+	now := time.Now()
+	incidents, err := s.db.GetIncidentsForMonitors(ids, now.Add(-statusPageIncidents), statusFeedLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]statusIncident, len(incidents))
+	for i, inc := range incidents {
+		result[i] = toStatusIncident(inc, now)
+	}
+
+	return result, nil
+}
+
+func statusTitle() string {
+	if configuration.Config.StatusPage.Title != "" {
+		return configuration.Config.StatusPage.Title
+	}
+
+	return "Service Status"
+}
+
+func monitorStatus(m models.Monitor, windows []models.Maintenance, now time.Time) string {
+	for _, window := range windows {
+		if window.AppliesTo(m) && window.IsActive(now) {
+			return "maintenance"
+		}
+	}
+
+	switch {
+	case m.IsUp == nil:
+		return "unknown"
+	case !*m.IsUp:
+		return "down"
+	case m.IsDegraded != nil && *m.IsDegraded:
+		return "degraded"
+	default:
+		return "up"
+	}
+}
+
+func monitorStatusText(status string) string {
+	switch status {
+	case "up":
+		return "Operational"
+	case "degraded":
+		return "Degraded performance"
+	case "down":
+		return "Outage"
+	case "maintenance":
+		return "Under maintenance"
+	default:
+		return "No data"
+	}
+}
+
+func overallStatus(counts map[string]int, total int) (string, string) {
+	switch {
+	case total == 0:
+		return "unknown", "No public monitors"
+	case counts["down"] == total:
+		return "outage", "Major outage"
+	case counts["down"] > 0:
+		return "outage", "Partial outage"
+	case counts["degraded"] > 0:
+		return "degraded", "Degraded performance"
+	case counts["maintenance"] > 0:
+		return "maintenance", "Under maintenance"
+	case counts["unknown"] == total:
+		return "unknown", "No data yet"
+	default:
+		return "operational", "All systems operational"
+	}
+}
+
+func uptimeLevel(uptime float64) string {
+	switch {
+	case uptime >= 99.9:
+		return "up"
+	case uptime >= 95:
+		return "partial"
+	default:
+		return "down"
+	}
+}
+
+func toStatusIncident(inc models.Incident, now time.Time) statusIncident {
+	title, ok := incidentTitles[inc.Type]
+	if !ok {
+		title = strings.ReplaceAll(string(inc.Type), "_", " ")
+	}
+
+	end := now
+	if inc.SolvedAt != nil {
+		end = *inc.SolvedAt
+	}
+
+	return statusIncident{
+		ID:         inc.ID,
+		Monitor:    inc.Monitor.DisplayName(),
+		Title:      title,
+		StartedAt:  inc.CreatedAt,
+		ResolvedAt: inc.SolvedAt,
+		Duration:   end.Sub(inc.CreatedAt).Round(time.Minute).String(),
+	}
+}
+
+func incidentUpdated(inc statusIncident) time.Time {
+	if inc.ResolvedAt != nil {
+		return *inc.ResolvedAt
+	}
+
+	return inc.StartedAt
+}
+
+func incidentSummary(inc statusIncident) string {
+	if inc.ResolvedAt == nil {
+		return fmt.Sprintf("%s is affected since %s.", inc.Monitor, inc.StartedAt.UTC().Format(time.RFC1123))
+	}
+
+	return fmt.Sprintf("%s was affected from %s, resolved after %s.", inc.Monitor, inc.StartedAt.UTC().Format(time.RFC1123), inc.Duration)
+}
+
+func appliesToAny(window models.Maintenance, monitors []models.Monitor) bool {
+	for _, m := range monitors {
+		if window.AppliesTo(m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func maintenanceSchedule(window models.Maintenance) string {
+	if !window.IsRecurring() {
+		return fmt.Sprintf("%s - %s", window.StartsAt.Format("2006-01-02 15:04 MST"), window.EndsAt.Format("2006-01-02 15:04 MST"))
+	}
+
+	days := "Every day"
+	if len(window.Weekdays) > 0 {
+		days = strings.Join(window.Weekdays, ", ")
+	}
+
+	from, to := window.From, window.To
+	if from == "" {
+		from = "00:00"
+	}
+	if to == "" {
+		to = "24:00"
+	}
+
+	schedule := fmt.Sprintf("%s %s-%s", days, from, to)
+	if window.Timezone != "" {
+		schedule += " " + window.Timezone
+	}
+
+	return schedule
+}
+
+// baseURL returns the scheme and host the request was made to, honouring
+// reverse proxy headers.
+func baseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+
+	return scheme + "://" + c.Request.Host
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+	"uptime-go/internal/net/database"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T) (*Server, *database.Database) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.InitializeTestDatabase()
+	if err != nil {
+		t.Fatalf("failed to initialize database: %v", err)
+	}
+
+	return NewServer(ServerConfig{}, db), db
+}
+
+func serve(s *Server, method string, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	s.router.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+	return recorder
+}
+
+func TestStatusPage(t *testing.T) {
+	s, db := newTestServer(t)
+
+	isUp := true
+	db.DB.Create(&models.Monitor{ID: "public", URL: "https://www.example.com", Name: "Website", Group: "Frontend", Public: true, IsUp: &isUp})
+	db.DB.Create(&models.Monitor{ID: "private", URL: "https://internal.example.com", Name: "Internal", IsUp: &isUp})
+	db.DB.Create(&[]models.MonitorHistory{
+		{MonitorID: "public", IsUp: true},
+		{MonitorID: "public", IsUp: false},
+	})
+	db.DB.Create(&models.Incident{ID: "outage", MonitorID: "public", Type: incident.Timeout, Description: "Request timed out: https://www.example.com"})
+	db.DB.Create(&models.Incident{ID: "hidden", MonitorID: "private", Type: incident.Timeout})
+
+	page, err := s.buildStatusPage(time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, page.Groups, 1)
+	assert.Equal(t, "Frontend", page.Groups[0].Name)
+	assert.Len(t, page.Groups[0].Monitors[0].Days, statusPageDays)
+	assert.Equal(t, 50.0, page.Groups[0].Monitors[0].Days[statusPageDays-1].Uptime)
+	assert.Len(t, page.ActiveIncidents, 1)
+	assert.Equal(t, "operational", page.Status)
+
+	response := serve(s, http.MethodGet, "/status")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), "Website")
+	assert.NotContains(t, response.Body.String(), "internal.example.com")
+
+	response = serve(s, http.MethodGet, "/status/feed.json")
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var feed struct {
+		Items []struct {
+			ID string `json:"id"`
+		} `json:"items"`
+	}
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &feed))
+	assert.Len(t, feed.Items, 1)
+	assert.Equal(t, "outage", feed.Items[0].ID)
+
+	response = serve(s, http.MethodGet, "/status/feed.atom")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), "urn:uptime-go:incident:outage")
+	assert.NotContains(t, response.Body.String(), "hidden")
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"uptime-go/internal/configuration"
+	"uptime-go/internal/helper"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+}
+
+type ErrorResponse struct {
+	Message string       `json:"message"`
+	Error   string       `json:"error,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+var registerValidators sync.Once
+
+// setupValidator reports fields by their JSON or query name and registers
+// the custom validation rules used in binding tags.
+func setupValidator() {
+	registerValidators.Do(func() {
+		engine, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+
+		engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+			for _, tag := range []string{"json", "form"} {
+				name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+				if name == "-" {
+					return ""
+				}
+				if name != "" {
+					return name
+				}
+			}
+			return field.Name
+		})
+
+		engine.RegisterValidation("duration", func(fl validator.FieldLevel) bool {
+			return helper.IsValidDuration(fl.Field().String())
+		})
+	})
+}
+
+// respondBindingError responds with 400 and one entry per invalid field when
+// the request failed validation.
+func respondBindingError(c *gin.Context, message string, err error) {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Message: message, Error: err.Error()})
+		return
+	}
+
+	fields := make([]FieldError, 0, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		// Drop the name of the top-level struct
+		_, field, _ := strings.Cut(fieldError.Namespace(), ".")
+		fields = append(fields, FieldError{
+			Field:   field,
+			Rule:    fieldError.Tag(),
+			Message: validationMessage(fieldError),
+		})
+	}
+
+	c.JSON(http.StatusBadRequest, ErrorResponse{Message: message, Error: err.Error(), Errors: fields})
+}
+
+// respondConfigProblems responds with 400 and the problems found by
+// configuration validation.
+func respondConfigProblems(c *gin.Context, problems []configuration.ValidationError) {
+	fields := make([]FieldError, 0, len(problems))
+	for _, problem := range problems {
+		fields = append(fields, FieldError{Field: problem.Field, Message: problem.Message})
+	}
+
+	c.JSON(http.StatusBadRequest, ErrorResponse{Message: "Invalid configuration", Errors: fields})
+}
+
+func validationMessage(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required":
+		return "is required"
+	case "duration":
+		return fmt.Sprintf("invalid duration %q, expected e.g. 30s, 5m, 1h30m or 31d", fieldError.Value())
+	case "datetime":
+		return fmt.Sprintf("invalid time %q, expected format %s", fieldError.Value(), fieldError.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", fieldError.Param())
+	case "min":
+		return fmt.Sprintf("must be at least %s", fieldError.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s", fieldError.Param())
+	default:
+		return fmt.Sprintf("failed on the %q rule", fieldError.Tag())
+	}
+}
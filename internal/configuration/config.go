@@ -1,10 +1,14 @@
 package configuration
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 	"uptime-go/internal/helper"
 	"uptime-go/internal/models"
 
@@ -19,12 +23,52 @@ const (
 )
 
 type MonitorConfig struct {
-	URL                      string `mapstructure:"url" yaml:"url" json:"url"`
-	Enabled                  bool   `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
-	Interval                 string `mapstructure:"interval" yaml:"interval" json:"interval"`
-	ResponseTimeThreshold    string `mapstructure:"response_time_threshold" yaml:"response_time_threshold" json:"response_time_threshold"`
-	CertificateMonitoring    bool   `mapstructure:"certificate_monitoring" yaml:"certificate_monitoring" json:"certificate_monitoring"`
-	CertificateExpiredBefore string `mapstructure:"certificate_expired_before" yaml:"certificate_expired_before" json:"certificate_expired_before"`
+	ID                       string            `mapstructure:"id" yaml:"id,omitempty" json:"id,omitempty"`
+	URL                      string            `mapstructure:"url" yaml:"url" json:"url" binding:"required"`
+	Name                     string            `mapstructure:"name" yaml:"name,omitempty" json:"name,omitempty"`
+	Group                    string            `mapstructure:"group" yaml:"group,omitempty" json:"group,omitempty"`
+	Public                   bool              `mapstructure:"public" yaml:"public,omitempty" json:"public,omitempty"`
+	Enabled                  bool              `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Interval                 string            `mapstructure:"interval" yaml:"interval" json:"interval" binding:"omitempty,duration"`
+	ResponseTimeThreshold    string            `mapstructure:"response_time_threshold" yaml:"response_time_threshold" json:"response_time_threshold" binding:"omitempty,duration"`
+	CertificateMonitoring    bool              `mapstructure:"certificate_monitoring" yaml:"certificate_monitoring" json:"certificate_monitoring"`
+	CertificateExpiredBefore string            `mapstructure:"certificate_expired_before" yaml:"certificate_expired_before" json:"certificate_expired_before" binding:"omitempty,duration"`
+	DependsOn                []string          `mapstructure:"depends_on" yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
+	DegradedThreshold        string            `mapstructure:"degraded_threshold" yaml:"degraded_threshold,omitempty" json:"degraded_threshold,omitempty" binding:"omitempty,duration"`
+	DegradedAverage          int               `mapstructure:"degraded_average" yaml:"degraded_average,omitempty" json:"degraded_average,omitempty" binding:"min=0"`
+	AnomalyDetection         bool              `mapstructure:"anomaly_detection" yaml:"anomaly_detection,omitempty" json:"anomaly_detection,omitempty"`
+	Tags                     []string          `mapstructure:"tags" yaml:"tags,omitempty" json:"tags,omitempty"`
+	Labels                   map[string]string `mapstructure:"labels" yaml:"labels,omitempty" json:"labels,omitempty"`
+}
+
+// MaintenanceConfig describes a maintenance window. One-off windows set
+// starts_at/ends_at (RFC3339); recurring windows set weekdays and a from/to
+// time of day (HH:MM) evaluated in timezone. A window covers the monitors
+// listed in monitors and the monitors with one of tags, every monitor when
+// both are empty.
+type MaintenanceConfig struct {
+	Name        string   `mapstructure:"name" yaml:"name" json:"name" binding:"required"`
+	Description string   `mapstructure:"description" yaml:"description,omitempty" json:"description,omitempty"`
+	Monitors    []string `mapstructure:"monitors" yaml:"monitors,omitempty" json:"monitors,omitempty"`
+	Tags        []string `mapstructure:"tags" yaml:"tags,omitempty" json:"tags,omitempty"`
+	StartsAt    string   `mapstructure:"starts_at" yaml:"starts_at,omitempty" json:"starts_at,omitempty" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	EndsAt      string   `mapstructure:"ends_at" yaml:"ends_at,omitempty" json:"ends_at,omitempty" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Weekdays    []string `mapstructure:"weekdays" yaml:"weekdays,omitempty" json:"weekdays,omitempty"`
+	From        string   `mapstructure:"from" yaml:"from,omitempty" json:"from,omitempty" binding:"omitempty,datetime=15:04"`
+	To          string   `mapstructure:"to" yaml:"to,omitempty" json:"to,omitempty" binding:"omitempty,datetime=15:04"`
+	Timezone    string   `mapstructure:"timezone" yaml:"timezone,omitempty" json:"timezone,omitempty"`
+}
+
+// NotifierConfig is a local notifier: incidents and their status changes are
+// posted to url as JSON, with the same payloads as sent to the master.
+type NotifierConfig struct {
+	Name    string            `mapstructure:"name" yaml:"name" json:"name"`
+	URL     string            `mapstructure:"url" yaml:"url" json:"url"`
+	Headers map[string]string `mapstructure:"headers" yaml:"headers,omitempty" json:"headers,omitempty"`
+}
+
+type StatusPageConfig struct {
+	Title string `mapstructure:"title" yaml:"title,omitempty" json:"title,omitempty"`
 }
 
 type AppConfig struct {
@@ -35,7 +79,16 @@ type AppConfig struct {
 		}
 	}
 
-	Monitor []*models.Monitor
+	// Standalone disables reporting to the master, incidents are only sent
+	// to the local notifiers
+	Standalone bool
+
+	StatusPage StatusPageConfig
+	Groups     map[string]GroupConfig
+	Notifiers  []NotifierConfig
+
+	Monitor     []*models.Monitor
+	Maintenance []*models.Maintenance
 }
 
 var Config AppConfig
@@ -48,17 +101,37 @@ func GetIncidentStatusURL(id uint64) string {
 	return fmt.Sprintf("%s/api/v1/incidents/%d/update-status", Config.Agent.MasterHost, id)
 }
 
-func Load(configPath string) error {
-	// Load agent config
-	agentConfig := viper.New()
-	agentConfig.SetConfigFile(OJTGUARDIAN_CONFIG)
-	agentConfig.SetConfigType("yaml")
-	if err := agentConfig.ReadInConfig(); err != nil {
-		return err
+// GetGroupConfig returns the configuration of a monitor group. Names are
+// compared case-insensitively as viper lowercases keys.
+func GetGroupConfig(name string) GroupConfig {
+	for key, group := range Config.Groups {
+		if strings.EqualFold(key, name) {
+			return group
+		}
 	}
 
-	if err := agentConfig.Unmarshal(&Config.Agent); err != nil {
-		return err
+	return GroupConfig{}
+}
+
+// Load reads the agent configuration at agentConfigPath and the monitor
+// configuration at configPath. Without agent configuration, or when
+// standalone is set, incidents are not reported to the master.
+func Load(configPath string, agentConfigPath string, standalone bool) error {
+	// Load agent config
+	Config.Standalone = standalone
+	if !standalone {
+		agentConfig := viper.New()
+		agentConfig.SetConfigType("yaml")
+		if err := readConfig(agentConfig, agentConfigPath); err != nil {
+			if !os.IsNotExist(err) {
+				return err
+			}
+
+			log.Warn().Str("path", agentConfigPath).Msg("agent configuration not found, running standalone without reporting to the master")
+			Config.Standalone = true
+		} else if err := agentConfig.Unmarshal(&Config.Agent); err != nil {
+			return err
+		}
 	}
 
 	// Load monitor config
@@ -79,89 +152,261 @@ func Load(configPath string) error {
 	monitorConfig.SetConfigFile(configPath)
 	monitorConfig.SetConfigType("yml")
 
-	if err := monitorConfig.ReadInConfig(); err != nil {
+	if err := readConfig(monitorConfig, configPath); err != nil {
 		if !os.IsNotExist(err) {
 			return err
 		}
 
+		if err := writeDefaultConfig(configPath); err != nil {
+			return err
+		}
 		log.Info().Msg("config file created with default site")
-		setDefaultMonitor(monitorConfig)
+
+		if err := readConfig(monitorConfig, configPath); err != nil {
+			return err
+		}
 	}
 
-	var rawMonitor []MonitorConfig
+	if err := monitorConfig.UnmarshalKey("groups", &Config.Groups); err != nil {
+		return err
+	}
+
+	settings := newMonitorSettings(monitorConfig.Get("defaults"), monitorConfig.Get("groups"), monitorConfig.Get("templates"))
+	rawMonitor, err := settings.monitors(monitorConfig.Get("monitor"), configPath)
+	if err != nil {
+		return err
+	}
+
+	if err := monitorConfig.UnmarshalKey("status_page", &Config.StatusPage); err != nil {
+		return err
+	}
+
+	if err := monitorConfig.UnmarshalKey("notifiers", &Config.Notifiers); err != nil {
+		return err
+	}
+
+	var rawMaintenance []MaintenanceConfig
 
-	if err := monitorConfig.UnmarshalKey("monitor", &rawMonitor); err != nil {
+	if err := monitorConfig.UnmarshalKey("maintenance", &rawMaintenance); err != nil {
 		return err
 	}
 
+	// Included files add monitors and maintenance windows
+	includes, _ := includedFiles(configPath, monitorConfig.GetStringSlice("include"))
+	for _, path := range includes {
+		includedConfig := viper.New()
+		includedConfig.SetConfigType("yml")
+		if err := readConfig(includedConfig, path); err != nil {
+			return err
+		}
+
+		// Included monitors share the settings of the main file
+		monitors, err := settings.monitors(includedConfig.Get("monitor"), path)
+		if err != nil {
+			return err
+		}
+
+		var windows []MaintenanceConfig
+		if err := includedConfig.UnmarshalKey("maintenance", &windows); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		log.Debug().Str("path", path).Int("monitors", len(monitors)).Msg("included configuration")
+		rawMonitor = append(rawMonitor, monitors...)
+		rawMaintenance = append(rawMaintenance, windows...)
+	}
+
+	// The file is left as is, it may hold references resolved above
 	if len(rawMonitor) <= 0 {
 		log.Info().Msg("no sites to monitor, adding default site...")
-		setDefaultMonitor(monitorConfig)
-		if err := monitorConfig.UnmarshalKey("monitor", &rawMonitor); err != nil {
+		monitor, err := defaultMonitor(settings)
+		if err != nil {
 			return err
 		}
+		rawMonitor = append(rawMonitor, monitor)
 	}
 
 	// Parse
+	ids := map[string]bool{}
 	for _, monitor := range rawMonitor {
 		if monitor.URL == "" {
 			log.Warn().Msg("found record with empty url")
 			continue
 		}
 
-		URL := helper.NormalizeURL(monitor.URL)
-		interval := helper.ParseDuration(monitor.Interval, "5m")
-		timeout := helper.ParseDuration(monitor.ResponseTimeThreshold, "30s")
-		certificateExpiredBefore := helper.ParseDuration(monitor.CertificateExpiredBefore, "31d")
+		parsed := monitor.Parse()
+		if ids[parsed.ID] {
+			log.Warn().Str("id", parsed.ID).Str("url", parsed.URL).Msg("skipping duplicate monitor")
+			continue
+		}
+
+		ids[parsed.ID] = true
+		Config.Monitor = append(Config.Monitor, parsed)
+	}
+
+	for _, raw := range rawMaintenance {
+		window, err := raw.Parse()
+		if err != nil {
+			log.Warn().Err(err).Msg("skipping invalid maintenance window")
+			continue
+		}
 
-		Config.Monitor = append(Config.Monitor, &models.Monitor{
-			URL:                      URL,
-			Enabled:                  monitor.Enabled,
-			Interval:                 interval,
-			ResponseTimeThreshold:    timeout,
-			CertificateMonitoring:    monitor.CertificateMonitoring,
-			CertificateExpiredBefore: &certificateExpiredBefore,
-		})
+		window.Source = models.MaintenanceSourceConfig
+		Config.Maintenance = append(Config.Maintenance, window)
+	}
+
+	// Invalid values fell back to defaults above, report them with their
+	// position
+	if problems, err := ValidateFile(configPath); err == nil {
+		for _, problem := range problems {
+			log.Warn().Msg(problem.Error())
+		}
 	}
 
 	return nil
 }
 
-func UpdateConfig(configPath string, jsonConfig []byte) error {
-	var config struct {
-		Monitor []MonitorConfig `json:"monitor"`
+// MonitorID returns the assigned ID of the monitor, or the ID derived from
+// its URL.
+func (c MonitorConfig) MonitorID() string {
+	if c.ID != "" {
+		return c.ID
+	}
+
+	return helper.MonitorID(helper.NormalizeURL(c.URL))
+}
+
+// Parse converts the raw monitor into a model with a normalized URL and
+// default durations. Dependencies on itself are dropped.
+func (c MonitorConfig) Parse() *models.Monitor {
+	URL := helper.NormalizeURL(c.URL)
+	certificateExpiredBefore := helper.ParseDuration(c.CertificateExpiredBefore, DefaultCertificateExpiredBefore)
+
+	var degradedThreshold time.Duration
+	if c.DegradedThreshold != "" {
+		degradedThreshold = helper.ParseDuration(c.DegradedThreshold, "")
+	}
+
+	self := models.Monitor{ID: c.MonitorID(), URL: URL}
+	var dependsOn []string
+	for _, parent := range c.DependsOn {
+		if ref := helper.NormalizeMonitorRef(parent); !self.Matches(ref) {
+			dependsOn = append(dependsOn, ref)
+		}
+	}
+
+	return &models.Monitor{
+		ID:                       c.MonitorID(),
+		URL:                      URL,
+		Name:                     c.Name,
+		Group:                    c.Group,
+		Public:                   c.Public,
+		Enabled:                  c.Enabled,
+		Interval:                 helper.ParseDuration(c.Interval, DefaultInterval),
+		ResponseTimeThreshold:    helper.ParseDuration(c.ResponseTimeThreshold, DefaultResponseTimeThreshold),
+		CertificateMonitoring:    c.CertificateMonitoring,
+		CertificateExpiredBefore: &certificateExpiredBefore,
+		DependsOn:                dependsOn,
+		DegradedThreshold:        degradedThreshold,
+		DegradedAverage:          c.DegradedAverage,
+		AnomalyDetection:         c.AnomalyDetection,
+		Tags:                     slices.Clone(c.Tags),
+		Labels:                   maps.Clone(c.Labels),
+	}
+}
+
+// Parse converts the raw window into a validated model with normalized
+// monitor references.
+func (c MaintenanceConfig) Parse() (*models.Maintenance, error) {
+	window := &models.Maintenance{
+		ID:          helper.GenerateRandomID(),
+		Name:        c.Name,
+		Description: c.Description,
+		Tags:        slices.Clone(c.Tags),
+		Weekdays:    c.Weekdays,
+		From:        c.From,
+		To:          c.To,
+		Timezone:    c.Timezone,
+	}
+
+	for _, ref := range c.Monitors {
+		window.Monitors = append(window.Monitors, helper.NormalizeMonitorRef(ref))
+	}
+
+	if c.StartsAt != "" {
+		startsAt, err := time.Parse(time.RFC3339, c.StartsAt)
+		if err != nil {
+			return nil, fmt.Errorf("maintenance window %q: invalid starts_at: %w", c.Name, err)
+		}
+		window.StartsAt = &startsAt
+	}
+
+	if c.EndsAt != "" {
+		endsAt, err := time.Parse(time.RFC3339, c.EndsAt)
+		if err != nil {
+			return nil, fmt.Errorf("maintenance window %q: invalid ends_at: %w", c.Name, err)
+		}
+		window.EndsAt = &endsAt
 	}
 
-	if err := json.Unmarshal(jsonConfig, &config); err != nil {
-		return fmt.Errorf("error while decoding config: %w", err)
+	if err := window.Validate(); err != nil {
+		return nil, err
 	}
 
-	yamlConfig, err := yaml.Marshal(config)
+	return window, nil
+}
+
+// UpdateConfigRequest is the monitoring configuration written by UpdateConfig.
+type UpdateConfigRequest struct {
+	StatusPage  *StatusPageConfig   `json:"status_page,omitempty" yaml:"status_page,omitempty"`
+	Monitor     []MonitorConfig     `json:"monitor" binding:"required,min=1,dive"`
+	Maintenance []MaintenanceConfig `json:"maintenance,omitempty" yaml:"maintenance,omitempty" binding:"dive"`
+}
+
+// readConfig reads the file at path into v after resolving environment
+// variable and file references.
+func readConfig(v *viper.Viper, path string) error {
+	content, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("error marshalling to YAML: %w", err)
+		return err
 	}
 
-	err = os.WriteFile(configPath, yamlConfig, 0644)
+	resolved, err := interpolate(content)
 	if err != nil {
-		return fmt.Errorf("error writing YAML file: %w", err)
+		return fmt.Errorf("%s: %w", path, err)
 	}
 
-	return nil
+	return v.ReadConfig(bytes.NewReader(resolved))
 }
 
-func setDefaultMonitor(v *viper.Viper) error {
-	v.Set("monitor", []MonitorConfig{
-		{
-			URL:                   "https://genbucyber.com",
-			Enabled:               true,
-			Interval:              "5m",
-			ResponseTimeThreshold: "10s",
-		},
+// defaultMonitor is monitored when the configuration has no monitors. Its
+// other settings come from the defaults block.
+func defaultMonitor(settings monitorSettings) (MonitorConfig, error) {
+	resolved, err := settings.resolve(map[string]any{
+		"url":                     "https://genbucyber.com",
+		"enabled":                 true,
+		"interval":                "5m",
+		"response_time_threshold": "10s",
 	})
+	if err != nil {
+		return MonitorConfig{}, err
+	}
 
-	if err := v.WriteConfig(); err != nil {
+	return decodeMonitor(resolved)
+}
+
+// writeDefaultConfig creates the configuration file at path with the default
+// monitor.
+func writeDefaultConfig(path string) error {
+	monitor, err := defaultMonitor(monitorSettings{})
+	if err != nil {
 		return err
 	}
 
-	return nil
+	content, err := yaml.Marshal(UpdateConfigRequest{Monitor: []MonitorConfig{monitor}})
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, content)
 }
@@ -0,0 +1,144 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"uptime-go/internal/helper"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAgentConfig(t *testing.T) {
+	previous := Config
+	t.Cleanup(func() { Config = previous })
+
+	configPath := writeConfig(t, `monitor:
+  - url: https://example.com
+    enabled: true
+notifiers:
+  - name: ops
+    url: https://hooks.example.com/uptime
+    headers:
+      Authorization: Bearer local
+`)
+	agentConfigPath := filepath.Join(t.TempDir(), "main.yml")
+
+	t.Run("missing", func(t *testing.T) {
+		Config = AppConfig{}
+		require.NoError(t, Load(configPath, agentConfigPath, false))
+		assert.True(t, Config.Standalone)
+		assert.Empty(t, Config.Agent.MasterHost)
+		assert.Len(t, Config.Monitor, 1)
+		assert.Equal(t, []NotifierConfig{{
+			Name:    "ops",
+			URL:     "https://hooks.example.com/uptime",
+			Headers: map[string]string{"authorization": "Bearer local"},
+		}}, Config.Notifiers)
+		assert.Equal(t, map[string]string{"authorization": Redacted}, Effective().Notifiers[0].Headers)
+	})
+
+	require.NoError(t, os.WriteFile(agentConfigPath, []byte("master_host: https://master.example.com\nauth:\n  token: secret\n"), 0644))
+
+	t.Run("present", func(t *testing.T) {
+		Config = AppConfig{}
+		require.NoError(t, Load(configPath, agentConfigPath, false))
+		assert.False(t, Config.Standalone)
+		assert.Equal(t, "https://master.example.com", Config.Agent.MasterHost)
+		assert.Equal(t, "secret", Config.Agent.Auth.Token)
+	})
+
+	t.Run("standalone", func(t *testing.T) {
+		Config = AppConfig{}
+		require.NoError(t, Load(configPath, agentConfigPath, true))
+		assert.True(t, Config.Standalone)
+		assert.Empty(t, Config.Agent.MasterHost)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(agentConfigPath, []byte("master_host: [\n"), 0644))
+		Config = AppConfig{}
+		assert.Error(t, Load(configPath, agentConfigPath, false))
+	})
+}
+
+func TestLoadMonitorIDs(t *testing.T) {
+	previous := Config
+	t.Cleanup(func() { Config = previous })
+
+	configPath := writeConfig(t, `monitor:
+  - url: https://example.com
+  - id: example-eu
+    url: https://example.com
+    interval: 1m
+  - url: https://example.com/
+`)
+
+	Config = AppConfig{}
+	require.NoError(t, Load(configPath, filepath.Join(t.TempDir(), "main.yml"), false))
+	require.Len(t, Config.Monitor, 2)
+	assert.Equal(t, helper.MonitorID("https://example.com"), Config.Monitor[0].ID)
+	assert.Equal(t, "example-eu", Config.Monitor[1].ID)
+	assert.Equal(t, "https://example.com", Config.Monitor[1].URL)
+
+	// Derived IDs are left out of the configuration
+	assert.Empty(t, NewMonitorConfig(Config.Monitor[0]).ID)
+	assert.Equal(t, "example-eu", NewMonitorConfig(Config.Monitor[1]).ID)
+}
+
+func TestLoadDefaultMonitor(t *testing.T) {
+	previous := Config
+	t.Cleanup(func() { Config = previous })
+	agentConfigPath := filepath.Join(t.TempDir(), "main.yml")
+
+	t.Run("missing file", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.yml")
+
+		Config = AppConfig{}
+		require.NoError(t, Load(configPath, agentConfigPath, true))
+		require.Len(t, Config.Monitor, 1)
+		assert.Equal(t, "https://genbucyber.com", Config.Monitor[0].URL)
+
+		content, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "url: https://genbucyber.com")
+	})
+
+	t.Run("no monitors", func(t *testing.T) {
+		t.Setenv("UPTIME_SLACK_TOKEN", "supersecret123")
+		content := `monitor: []
+notifiers:
+  - name: slack
+    url: https://hooks.example.com/${UPTIME_SLACK_TOKEN}
+`
+		configPath := writeConfig(t, content)
+
+		Config = AppConfig{}
+		require.NoError(t, Load(configPath, agentConfigPath, true))
+		require.Len(t, Config.Monitor, 1)
+		assert.Equal(t, "https://genbucyber.com", Config.Monitor[0].URL)
+		assert.Equal(t, "https://hooks.example.com/supersecret123", Config.Notifiers[0].URL)
+
+		// Resolved references are not written back
+		written, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(written))
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		configPath := writeConfig(t, `defaults:
+  certificate_monitoring: true
+  tags: [ops]
+monitor: []
+`)
+
+		Config = AppConfig{}
+		require.NoError(t, Load(configPath, agentConfigPath, true))
+		require.Len(t, Config.Monitor, 1)
+		assert.True(t, Config.Monitor[0].CertificateMonitoring)
+		assert.Equal(t, []string{"ops"}, Config.Monitor[0].Tags)
+		assert.Equal(t, 5*time.Minute, Config.Monitor[0].Interval)
+	})
+}
@@ -0,0 +1,223 @@
+package configuration
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+	"uptime-go/internal/helper"
+	"uptime-go/internal/models"
+)
+
+// Redacted replaces secrets in configuration returned to clients
+const Redacted = "********"
+
+// EffectiveConfig is the running configuration as returned by the API.
+type EffectiveConfig struct {
+	Agent       AgentConfig            `json:"agent"`
+	StatusPage  StatusPageConfig       `json:"status_page"`
+	Groups      map[string]GroupConfig `json:"groups,omitempty"`
+	Notifiers   []NotifierConfig       `json:"notifiers,omitempty"`
+	Monitor     []MonitorConfig        `json:"monitor"`
+	Maintenance []MaintenanceConfig    `json:"maintenance"`
+}
+
+type AgentConfig struct {
+	MasterHost string `json:"master_host"`
+	Token      string `json:"token,omitempty"`
+	Standalone bool   `json:"standalone"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type MonitorChange struct {
+	ID      string        `json:"id"`
+	URL     string        `json:"url"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// ConfigDiff lists the monitors that a new configuration would add, remove
+// or change compared to the running set.
+type ConfigDiff struct {
+	Added     []MonitorConfig `json:"added"`
+	Removed   []MonitorConfig `json:"removed"`
+	Changed   []MonitorChange `json:"changed"`
+	Unchanged int             `json:"unchanged"`
+}
+
+// NewMonitorConfig converts a monitor back to its configuration, with
+// durations in the shortest form accepted by ParseDuration.
+func NewMonitorConfig(monitor *models.Monitor) MonitorConfig {
+	var certificateExpiredBefore string
+	if monitor.CertificateExpiredBefore != nil {
+		certificateExpiredBefore = helper.FormatDuration(*monitor.CertificateExpiredBefore)
+	}
+
+	// Derived IDs are not part of the configuration
+	var id string
+	if monitor.ID != helper.MonitorID(monitor.URL) {
+		id = monitor.ID
+	}
+
+	return MonitorConfig{
+		ID:                       id,
+		URL:                      monitor.URL,
+		Name:                     monitor.Name,
+		Group:                    monitor.Group,
+		Public:                   monitor.Public,
+		Enabled:                  monitor.Enabled,
+		Interval:                 helper.FormatDuration(monitor.Interval),
+		ResponseTimeThreshold:    helper.FormatDuration(monitor.ResponseTimeThreshold),
+		CertificateMonitoring:    monitor.CertificateMonitoring,
+		CertificateExpiredBefore: certificateExpiredBefore,
+		DependsOn:                monitor.DependsOn,
+		DegradedThreshold:        helper.FormatDuration(monitor.DegradedThreshold),
+		DegradedAverage:          monitor.DegradedAverage,
+		AnomalyDetection:         monitor.AnomalyDetection,
+		Tags:                     monitor.Tags,
+		Labels:                   monitor.Labels,
+	}
+}
+
+func NewMaintenanceConfig(window *models.Maintenance) MaintenanceConfig {
+	config := MaintenanceConfig{
+		Name:        window.Name,
+		Description: window.Description,
+		Monitors:    window.Monitors,
+		Tags:        window.Tags,
+		Weekdays:    window.Weekdays,
+		From:        window.From,
+		To:          window.To,
+		Timezone:    window.Timezone,
+	}
+
+	if window.StartsAt != nil {
+		config.StartsAt = window.StartsAt.Format(time.RFC3339)
+	}
+	if window.EndsAt != nil {
+		config.EndsAt = window.EndsAt.Format(time.RFC3339)
+	}
+
+	return config
+}
+
+// Effective returns the loaded configuration with secrets redacted: the agent
+// token, notifier headers and values resolved from environment variables and
+// files.
+func Effective() EffectiveConfig {
+	effective := EffectiveConfig{
+		Agent:       AgentConfig{MasterHost: Config.Agent.MasterHost, Standalone: Config.Standalone},
+		StatusPage:  Config.StatusPage,
+		Groups:      Config.Groups,
+		Monitor:     []MonitorConfig{},
+		Maintenance: []MaintenanceConfig{},
+	}
+
+	if Config.Agent.Auth.Token != "" {
+		effective.Agent.Token = Redacted
+	}
+
+	for _, monitor := range Config.Monitor {
+		effective.Monitor = append(effective.Monitor, NewMonitorConfig(monitor))
+	}
+
+	for _, window := range Config.Maintenance {
+		effective.Maintenance = append(effective.Maintenance, NewMaintenanceConfig(window))
+	}
+
+	// Headers usually carry credentials
+	for _, notifier := range Config.Notifiers {
+		headers := map[string]string{}
+		for name := range notifier.Headers {
+			headers[name] = Redacted
+		}
+		notifier.Headers = headers
+		effective.Notifiers = append(effective.Notifiers, notifier)
+	}
+
+	redactStrings(reflect.ValueOf(&effective).Elem())
+
+	return effective
+}
+
+// Diff compares the monitors of the request with the running monitors by
+// ID. Both sides are normalized, so "60s" and "1m" are equal.
+func (r UpdateConfigRequest) Diff(running []*models.Monitor) ConfigDiff {
+	diff := ConfigDiff{
+		Added:   []MonitorConfig{},
+		Removed: []MonitorConfig{},
+		Changed: []MonitorChange{},
+	}
+
+	current := map[string]MonitorConfig{}
+	for _, monitor := range running {
+		current[monitor.ID] = NewMonitorConfig(monitor)
+	}
+
+	seen := map[string]bool{}
+	for _, raw := range r.Monitor {
+		if raw.URL == "" {
+			continue
+		}
+
+		parsed := raw.Parse()
+		next := NewMonitorConfig(parsed)
+		seen[parsed.ID] = true
+
+		previous, exists := current[parsed.ID]
+		if !exists {
+			diff.Added = append(diff.Added, next)
+			continue
+		}
+
+		if changes := diffMonitorConfig(previous, next); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, MonitorChange{ID: parsed.ID, URL: next.URL, Changes: changes})
+		} else {
+			diff.Unchanged++
+		}
+	}
+
+	for _, monitor := range running {
+		if !seen[monitor.ID] {
+			diff.Removed = append(diff.Removed, current[monitor.ID])
+		}
+	}
+
+	return diff
+}
+
+func diffMonitorConfig(previous MonitorConfig, next MonitorConfig) []FieldChange {
+	var changes []FieldChange
+
+	previousValue := reflect.ValueOf(previous)
+	nextValue := reflect.ValueOf(next)
+	for _, field := range reflect.VisibleFields(previousValue.Type()) {
+		from := previousValue.FieldByIndex(field.Index).Interface()
+		to := nextValue.FieldByIndex(field.Index).Interface()
+
+		switch fromValue := from.(type) {
+		case []string:
+			if slices.Equal(fromValue, to.([]string)) {
+				continue
+			}
+		case map[string]string:
+			if maps.Equal(fromValue, to.(map[string]string)) {
+				continue
+			}
+		default:
+			if from == to {
+				continue
+			}
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		changes = append(changes, FieldChange{Field: name, From: from, To: to})
+	}
+
+	return changes
+}
@@ -0,0 +1,203 @@
+package configuration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DropInDir is the directory next to the configuration file whose *.yml and
+// *.yaml files are included after the include patterns.
+const DropInDir = "conf.d"
+
+// fileConfig is the layout of the configuration file.
+type fileConfig struct {
+	Include     []string                   `yaml:"include"`
+	StatusPage  *StatusPageConfig          `yaml:"status_page"`
+	Defaults    MonitorConfig              `yaml:"defaults"`
+	Templates   map[string]extendsConfig   `yaml:"templates"`
+	Groups      map[string]groupFileConfig `yaml:"groups"`
+	Notifiers   []NotifierConfig           `yaml:"notifiers"`
+	Monitor     []extendsConfig            `yaml:"monitor"`
+	Maintenance []MaintenanceConfig        `yaml:"maintenance"`
+}
+
+// includedConfig is the layout of included files, which only add monitors
+// and maintenance windows.
+type includedConfig struct {
+	Monitor     []extendsConfig     `yaml:"monitor"`
+	Maintenance []MaintenanceConfig `yaml:"maintenance"`
+}
+
+// rawConfig is the configuration file with monitor settings left undecoded,
+// so unset keys can be told apart from zero values.
+type rawConfig struct {
+	Include     []string            `yaml:"include"`
+	Defaults    any                 `yaml:"defaults"`
+	Groups      any                 `yaml:"groups"`
+	Templates   any                 `yaml:"templates"`
+	Monitor     any                 `yaml:"monitor"`
+	Maintenance []MaintenanceConfig `yaml:"maintenance"`
+}
+
+func (c rawConfig) settings() monitorSettings {
+	return newMonitorSettings(c.Defaults, c.Groups, c.Templates)
+}
+
+// includedFiles returns the files matching the include patterns, relative to
+// the directory of the configuration file, followed by the drop-in files.
+// Each file is returned once, in lexical order per pattern. Patterns that
+// are invalid or match nothing are reported as problems.
+func includedFiles(configPath string, patterns []string) ([]string, []ValidationError) {
+	dir := filepath.Dir(configPath)
+	dropIns := []string{filepath.Join(DropInDir, "*.yml"), filepath.Join(DropInDir, "*.yaml")}
+
+	seen := map[string]bool{}
+	if mainPath, err := filepath.Abs(configPath); err == nil {
+		seen[mainPath] = true
+	}
+
+	var files []string
+	var problems []ValidationError
+	for i, pattern := range append(slices.Clone(patterns), dropIns...) {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if i < len(patterns) {
+			field := fmt.Sprintf("include[%d]", i)
+			switch {
+			case err != nil:
+				problems = append(problems, ValidationError{File: configPath, Field: field, Message: fmt.Sprintf("invalid pattern %q: %v", patterns[i], err)})
+			case len(matches) == 0:
+				problems = append(problems, ValidationError{File: configPath, Field: field, Message: fmt.Sprintf("no files match %q", patterns[i])})
+			}
+		}
+
+		for _, match := range matches {
+			path, err := filepath.Abs(match)
+			if err != nil || seen[path] {
+				continue
+			}
+			if info, err := os.Stat(match); err != nil || info.IsDir() {
+				continue
+			}
+
+			seen[path] = true
+			files = append(files, match)
+		}
+	}
+
+	return files, problems
+}
+
+// withoutIncluded removes the monitors and maintenance windows defined in the
+// files included by current from config, as UpdateConfig only writes the main
+// file. Changing them is a problem: they are changed in their own file.
+func withoutIncluded(configPath string, current []byte, config UpdateConfigRequest) (UpdateConfigRequest, []ValidationError, error) {
+	var main rawConfig
+	if err := yaml.Unmarshal(current, &main); err != nil {
+		return config, nil, fmt.Errorf("error parsing current configuration: %w", err)
+	}
+
+	// Values are compared as clients see them, normalized and redacted
+	comparable := func(monitor MonitorConfig) MonitorConfig {
+		monitor = NewMonitorConfig(monitor.Parse())
+		redactStrings(reflect.ValueOf(&monitor).Elem())
+		return monitor
+	}
+	comparableWindow := func(raw MaintenanceConfig) MaintenanceConfig {
+		if window, err := raw.Parse(); err == nil {
+			raw = NewMaintenanceConfig(window)
+		}
+		redactStrings(reflect.ValueOf(&raw).Elem())
+		return raw
+	}
+
+	type includedMonitor struct {
+		path    string
+		monitor MonitorConfig
+	}
+	type includedWindow struct {
+		path   string
+		window MaintenanceConfig
+	}
+	monitors := map[string]includedMonitor{}
+	windows := map[string]includedWindow{}
+
+	paths, _ := includedFiles(configPath, main.Include)
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return config, nil, fmt.Errorf("error reading %s: %w", path, err)
+		}
+
+		resolved, err := interpolate(content)
+		if err != nil {
+			return config, nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		var included rawConfig
+		if err := yaml.Unmarshal(resolved, &included); err != nil {
+			return config, nil, fmt.Errorf("error parsing %s: %w", path, err)
+		}
+
+		includedMonitors, err := main.settings().monitors(included.Monitor, path)
+		if err != nil {
+			return config, nil, err
+		}
+
+		for _, monitor := range includedMonitors {
+			monitor = comparable(monitor)
+			monitors[monitor.MonitorID()] = includedMonitor{path, monitor}
+		}
+		for _, window := range included.Maintenance {
+			window = comparableWindow(window)
+			windows[window.Name] = includedWindow{path, window}
+		}
+	}
+
+	var problems []ValidationError
+	var monitorConfigs []MonitorConfig
+	for i, monitor := range config.Monitor {
+		included, ok := monitors[comparable(monitor).MonitorID()]
+		if !ok {
+			monitorConfigs = append(monitorConfigs, monitor)
+			continue
+		}
+
+		if changes := diffMonitorConfig(included.monitor, comparable(monitor)); len(changes) > 0 {
+			problems = append(problems, ValidationError{
+				Field:   fmt.Sprintf("monitor[%d]", i),
+				Message: fmt.Sprintf("defined in %s, change it there", included.path),
+			})
+		}
+	}
+	config.Monitor = monitorConfigs
+
+	if config.Maintenance != nil {
+		maintenanceConfigs := []MaintenanceConfig{}
+		for i, window := range config.Maintenance {
+			included, ok := windows[comparableWindow(window).Name]
+			if !ok {
+				maintenanceConfigs = append(maintenanceConfigs, window)
+				continue
+			}
+
+			if !reflect.DeepEqual(included.window, comparableWindow(window)) {
+				problems = append(problems, ValidationError{
+					Field:   fmt.Sprintf("maintenance[%d]", i),
+					Message: fmt.Sprintf("defined in %s, change it there", included.path),
+				})
+			}
+		}
+		config.Maintenance = maintenanceConfigs
+	}
+
+	return config, problems, nil
+}
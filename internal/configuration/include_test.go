@@ -0,0 +1,129 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigDir writes files relative to a temporary directory and returns
+// the path of config.yml in it.
+func writeConfigDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	return filepath.Join(dir, "config.yml")
+}
+
+func TestIncludedFiles(t *testing.T) {
+	configPath := writeConfigDir(t, map[string]string{
+		"config.yml":          "include: [teams/*.yml, config.yml, missing.yml]\n",
+		"teams/b.yml":         "",
+		"teams/a.yml":         "",
+		"conf.d/z.yml":        "",
+		"conf.d/a.yaml":       "",
+		"conf.d/a.yml.1.bak":  "",
+		"conf.d/nested/x.yml": "",
+	})
+	dir := filepath.Dir(configPath)
+
+	files, problems := includedFiles(configPath, []string{"teams/*.yml", "config.yml", "conf.d/z.yml", "missing.yml", "["})
+	assert.Equal(t, []string{
+		filepath.Join(dir, "teams/a.yml"),
+		filepath.Join(dir, "teams/b.yml"),
+		filepath.Join(dir, "conf.d/z.yml"),
+		filepath.Join(dir, "conf.d/a.yaml"),
+	}, files)
+
+	require.Len(t, problems, 2)
+	assert.Equal(t, "include[3]", problems[0].Field)
+	assert.Equal(t, `no files match "missing.yml"`, problems[0].Message)
+	assert.Equal(t, "include[4]", problems[1].Field)
+}
+
+func TestValidateFileIncludes(t *testing.T) {
+	configPath := writeConfigDir(t, map[string]string{
+		"config.yml": `include:
+  - teams/*.yml
+monitor:
+  - url: https://lb.example.com
+`,
+		"teams/web.yml": `monitor:
+  - url: https://app.example.com
+    depends_on: [https://lb.example.com]
+status_page:
+  title: Web
+`,
+		"conf.d/api.yml": `monitor:
+  - url: https://api.example.com
+  - url: https://app.example.com/
+`,
+	})
+	dir := filepath.Dir(configPath)
+
+	problems, err := ValidateFile(configPath)
+	require.NoError(t, err)
+	require.Len(t, problems, 2)
+
+	assert.Equal(t, ValidationError{
+		File: filepath.Join(dir, "teams/web.yml"), Line: 4, Column: 1,
+		Field: "status_page", Message: "unknown field",
+	}, problems[0])
+	assert.Equal(t, ValidationError{
+		File: filepath.Join(dir, "conf.d/api.yml"), Line: 3, Column: 10,
+		Field:   "monitor[1].url",
+		Message: "duplicate of monitor[0] in " + filepath.Join(dir, "teams/web.yml") + " (https://app.example.com), set an id to monitor it twice",
+	}, problems[1])
+}
+
+func TestUpdateConfigIncludedMonitors(t *testing.T) {
+	configPath := writeConfigDir(t, map[string]string{
+		"config.yml": `monitor:
+  - url: https://a.example.com
+    enabled: true
+`,
+		"conf.d/team.yml": `monitor:
+  - url: https://team.example.com
+    enabled: true
+    interval: 1m
+maintenance:
+  - name: team deploy
+    weekdays: [mon]
+    from: "10:00"
+    to: "11:00"
+`,
+	})
+
+	monitors := []MonitorConfig{
+		{URL: "https://a.example.com", Enabled: true, Interval: "2m"},
+		{URL: "https://team.example.com", Enabled: true, Interval: "60s"},
+	}
+	windows := []MaintenanceConfig{{Name: "team deploy", Weekdays: []string{"mon"}, From: "10:00", To: "11:00"}}
+	require.NoError(t, UpdateConfig(configPath, UpdateConfigRequest{Monitor: monitors, Maintenance: windows}))
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "interval: 2m")
+	assert.NotContains(t, string(content), "team.example.com")
+	assert.NotContains(t, string(content), "team deploy")
+
+	monitors[1].Interval = "5m"
+	windows[0].To = "12:00"
+	err = UpdateConfig(configPath, UpdateConfigRequest{Monitor: monitors, Maintenance: windows})
+
+	var invalid *InvalidConfigError
+	require.ErrorAs(t, err, &invalid)
+	require.Len(t, invalid.Problems, 2)
+	assert.Equal(t, "monitor[1]", invalid.Problems[0].Field)
+	assert.Contains(t, invalid.Problems[0].Message, filepath.Join("conf.d", "team.yml")+", change it there")
+	assert.Equal(t, "maintenance[0]", invalid.Problems[1].Field)
+}
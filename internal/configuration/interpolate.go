@@ -0,0 +1,226 @@
+package configuration
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// filePrefix marks a value read from a file, e.g. "file:/run/secrets/token"
+const filePrefix = "file:"
+
+// minSecretLength is the length from which values read from the environment
+// are redacted. Shorter values, such as "5m" or "prod", are more likely
+// settings than secrets and would redact unrelated text.
+const minSecretLength = 8
+
+// referencePattern matches ${VAR}, ${VAR:-default} and the $$ escape
+var referencePattern = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+var (
+	secretsMutex sync.RWMutex
+	secrets      []string
+)
+
+// hasReference reports whether value needs to be resolved.
+func hasReference(value string) bool {
+	return strings.HasPrefix(value, filePrefix) || referencePattern.MatchString(value)
+}
+
+// resolveReferences replaces ${VAR} and ${VAR:-default} with the environment
+// and reads the file of a value starting with "file:". The default is used
+// when the variable is unset or empty; an unset variable without default is
+// an error. "$$" is a literal "$".
+//
+// The values read from files and the values of at least minSecretLength read
+// from the environment are returned as well, as they may be secrets.
+func resolveReferences(value string) (string, []string, error) {
+	var resolved []string
+	var missing []string
+
+	value = referencePattern.ReplaceAllStringFunc(value, func(reference string) string {
+		if reference == "$$" {
+			return "$"
+		}
+
+		match := referencePattern.FindStringSubmatch(reference)
+		name, defaultValue := match[1], match[2]
+		env, ok := os.LookupEnv(name)
+		if env == "" && strings.Contains(reference, ":-") {
+			return defaultValue // Written in the configuration, not a secret
+		}
+		if !ok {
+			missing = append(missing, name)
+			return reference
+		}
+
+		if len(env) >= minSecretLength {
+			resolved = append(resolved, env)
+		}
+		return env
+	})
+
+	if len(missing) > 0 {
+		return "", nil, fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+	}
+
+	if path, ok := strings.CutPrefix(value, filePrefix); ok {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", nil, fmt.Errorf("error reading %s: %w", path, err)
+		}
+
+		value = strings.TrimRight(string(content), "\r\n")
+		resolved = append(resolved, value)
+	}
+
+	return value, resolved, nil
+}
+
+// interpolate resolves the references in the values of a YAML document.
+// Resolved values are kept as strings and remembered for Redact.
+func interpolate(content []byte) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+
+	problems := interpolateNode(&document)
+	if len(problems) > 0 {
+		messages := make([]string, 0, len(problems))
+		for _, problem := range problems {
+			messages = append(messages, problem.Error())
+		}
+		return nil, fmt.Errorf("error resolving configuration: %s", strings.Join(messages, "; "))
+	}
+
+	if document.Kind == 0 {
+		return content, nil
+	}
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// interpolateNode resolves the references in the scalar values under node in
+// place and returns the references that cannot be resolved. Mapping keys are
+// left as is.
+func interpolateNode(node *yaml.Node) []ValidationError {
+	var problems []ValidationError
+
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			problems = append(problems, interpolateNode(child)...)
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			problems = append(problems, interpolateNode(node.Content[i])...)
+		}
+	case yaml.ScalarNode:
+		if !hasReference(node.Value) {
+			break
+		}
+
+		value, resolved, err := resolveReferences(node.Value)
+		if err != nil {
+			problems = append(problems, ValidationError{Line: node.Line, Column: node.Column, Message: err.Error()})
+			break
+		}
+
+		addSecrets(resolved...)
+		node.Value = value
+		node.Tag = "!!str"
+	}
+
+	return problems
+}
+
+// redactedValue returns value as clients see it, resolved and redacted, so a
+// value read back from the API matches the reference it was resolved from.
+func redactedValue(value string) string {
+	if hasReference(value) {
+		if resolved, _, err := resolveReferences(value); err == nil {
+			value = resolved
+		}
+	}
+
+	return Redact(value)
+}
+
+func addSecrets(values ...string) {
+	secretsMutex.Lock()
+	defer secretsMutex.Unlock()
+
+	for _, value := range values {
+		if value != "" && !slices.Contains(secrets, value) {
+			secrets = append(secrets, value)
+		}
+	}
+
+	// Longest first, so a secret containing another is fully redacted
+	sort.SliceStable(secrets, func(i, j int) bool {
+		return len(secrets[i]) > len(secrets[j])
+	})
+}
+
+// Redact replaces the secrets resolved from environment variables and files
+// in text.
+func Redact(text string) string {
+	secretsMutex.RLock()
+	defer secretsMutex.RUnlock()
+
+	for _, secret := range secrets {
+		text = strings.ReplaceAll(text, secret, Redacted)
+	}
+
+	return text
+}
+
+// redactStrings redacts every string reachable from value in place. Slices
+// and maps of strings are copied first, as they may be shared with the
+// running configuration.
+func redactStrings(value reflect.Value) {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(Redact(value.String()))
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).IsExported() {
+				redactStrings(value.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.String && !value.IsNil() {
+			value.Set(reflect.AppendSlice(reflect.MakeSlice(value.Type(), 0, value.Len()), value))
+		}
+		for i := 0; i < value.Len(); i++ {
+			redactStrings(value.Index(i))
+		}
+	case reflect.Map:
+		if value.Type().Elem().Kind() != reflect.String || value.IsNil() {
+			break
+		}
+		redacted := reflect.MakeMapWithSize(value.Type(), value.Len())
+		for entries := value.MapRange(); entries.Next(); {
+			redacted.SetMapIndex(entries.Key(), reflect.ValueOf(Redact(entries.Value().String())).Convert(value.Type().Elem()))
+		}
+		value.Set(redacted)
+	}
+}
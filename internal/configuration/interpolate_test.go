@@ -0,0 +1,150 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"uptime-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resetSecrets(t *testing.T) {
+	t.Cleanup(func() {
+		secretsMutex.Lock()
+		secrets = nil
+		secretsMutex.Unlock()
+	})
+}
+
+func TestResolveReferences(t *testing.T) {
+	t.Setenv("UPTIME_TOKEN", "s3cret-token")
+	t.Setenv("UPTIME_ENV", "prod")
+	t.Setenv("UPTIME_EMPTY", "")
+
+	secretFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(secretFile, []byte("abc\n"), 0600))
+	t.Setenv("UPTIME_SECRET_FILE", secretFile)
+
+	tests := []struct {
+		value    string
+		expected string
+		secrets  []string
+		err      string
+	}{
+		{value: "plain", expected: "plain"},
+		{value: "Bearer ${UPTIME_TOKEN}", expected: "Bearer s3cret-token", secrets: []string{"s3cret-token"}},
+		{value: "${UPTIME_ENV}", expected: "prod"},
+		{value: "${UPTIME_MISSING:-fallback}", expected: "fallback"},
+		{value: "${UPTIME_EMPTY:-fallback}", expected: "fallback"},
+		{value: "${UPTIME_EMPTY}", expected: ""},
+		{value: "costs $$5", expected: "costs $5"},
+		{value: "file:" + secretFile, expected: "abc", secrets: []string{"abc"}},
+		{value: "file:${UPTIME_SECRET_FILE}", expected: "abc", secrets: []string{secretFile, "abc"}},
+		{value: "${UPTIME_MISSING}", err: "environment variable UPTIME_MISSING is not set"},
+		{value: "file:/nonexistent/token", err: "error reading /nonexistent/token"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			value, secrets, err := resolveReferences(test.value)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, value)
+			assert.Equal(t, test.secrets, secrets)
+		})
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	resetSecrets(t)
+	t.Setenv("UPTIME_HOST", "internal.example.com")
+	t.Setenv("UPTIME_ENABLED", "true")
+	t.Setenv("UPTIME_CHECK_INTERVAL", "5m")
+
+	resolved, err := interpolate([]byte(`monitor:
+  - url: https://${UPTIME_HOST}/health
+    enabled: ${UPTIME_ENABLED}
+    interval: ${UPTIME_CHECK_INTERVAL}
+    response_time_threshold: ${UPTIME_TIMEOUT:-1m}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, `monitor:
+    - url: https://internal.example.com/health
+      enabled: "true"
+      interval: 5m
+      response_time_threshold: 1m
+`, string(resolved))
+
+	assert.Equal(t, "https://********/health", Redact("https://internal.example.com/health"))
+	assert.Equal(t, "1m", Redact("1m"), "defaults are not secrets")
+	assert.Equal(t, "15m", Redact("15m"), "short values are not secrets")
+
+	_, err = interpolate([]byte("monitor:\n  - url: ${UPTIME_MISSING}\n"))
+	assert.ErrorContains(t, err, "2:10: environment variable UPTIME_MISSING is not set")
+}
+
+func TestValidateFileReferences(t *testing.T) {
+	resetSecrets(t)
+	t.Setenv("UPTIME_ENABLED", "true")
+
+	problems, err := ValidateFile(writeConfig(t, `monitor:
+  - url: https://example.com
+    enabled: ${UPTIME_ENABLED}
+  - url: ${UPTIME_MISSING}
+`))
+	require.NoError(t, err)
+	require.Len(t, problems, 1)
+	assert.Equal(t, 4, problems[0].Line)
+	assert.Equal(t, "environment variable UPTIME_MISSING is not set", problems[0].Message)
+}
+
+func TestEffectiveRedactsSecrets(t *testing.T) {
+	resetSecrets(t)
+	addSecrets("s3cret")
+
+	previous := Config
+	t.Cleanup(func() { Config = previous })
+
+	dependsOn := []string{"https://lb.example.com/?token=s3cret"}
+	Config = AppConfig{Monitor: []*models.Monitor{
+		{URL: "https://example.com/?token=s3cret", Interval: 60e9, DependsOn: dependsOn},
+	}}
+	Config.Agent.Auth.Token = "agent-token"
+
+	effective := Effective()
+	assert.Equal(t, Redacted, effective.Agent.Token)
+	assert.Equal(t, "https://example.com/?token=********", effective.Monitor[0].URL)
+	assert.Equal(t, []string{"https://lb.example.com/?token=********"}, effective.Monitor[0].DependsOn)
+	assert.Equal(t, "https://lb.example.com/?token=s3cret", dependsOn[0], "running configuration is not modified")
+}
+
+func TestUpdateConfigKeepsReferences(t *testing.T) {
+	resetSecrets(t)
+	t.Setenv("UPTIME_HOST", "internal.example.com")
+
+	configPath := writeConfig(t, `monitor:
+  - url: https://${UPTIME_HOST}/health # internal
+    enabled: true
+    interval: 5m
+    response_time_threshold: 10s
+`)
+	_, err := ValidateFile(configPath) // Registers the secrets, like Load
+	require.NoError(t, err)
+
+	for _, url := range []string{"https://internal.example.com/health", "https://********/health"} {
+		require.NoError(t, UpdateConfig(configPath, UpdateConfigRequest{
+			Monitor: []MonitorConfig{{URL: url, Enabled: true, Interval: "1m", ResponseTimeThreshold: "10s"}},
+		}))
+
+		content, err := os.ReadFile(configPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "url: https://${UPTIME_HOST}/health # internal")
+		assert.Contains(t, string(content), "interval: 1m")
+	}
+}
@@ -0,0 +1,178 @@
+package configuration
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+// Built-in monitor defaults, overridden by the defaults block
+const (
+	DefaultInterval                 = "5m"
+	DefaultResponseTimeThreshold    = "30s"
+	DefaultCertificateExpiredBefore = "31d"
+)
+
+// GroupConfig holds the settings of a group that are not monitor settings.
+type GroupConfig struct {
+	Notify NotifyConfig `mapstructure:"notify" yaml:"notify,omitempty" json:"notify,omitempty"`
+}
+
+// NotifyConfig routes the incidents of a group: tags are added to the
+// incidents reported to the master.
+type NotifyConfig struct {
+	Tags []string `mapstructure:"tags" yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// extendsConfig is a monitor or a template, which may extend a template.
+type extendsConfig struct {
+	MonitorConfig `yaml:",inline"`
+	Extends       string `yaml:"extends"`
+}
+
+// groupFileConfig is a group: shared monitor settings and its GroupConfig.
+type groupFileConfig struct {
+	MonitorConfig `yaml:",inline"`
+	GroupConfig   `yaml:",inline"`
+}
+
+// monitorSettings are the settings shared by monitors, from the least to the
+// most specific: defaults, the settings of their group and the templates
+// they extend. Values are raw maps, so unset keys are not overridden.
+type monitorSettings struct {
+	defaults  map[string]any
+	groups    map[string]any
+	templates map[string]any
+}
+
+func newMonitorSettings(defaults any, groups any, templates any) monitorSettings {
+	settings := monitorSettings{}
+	settings.defaults, _ = defaults.(map[string]any)
+	settings.groups, _ = groups.(map[string]any)
+	settings.templates, _ = templates.(map[string]any)
+	return settings
+}
+
+// templateChain returns the template named name followed by the templates it
+// extends.
+func (s monitorSettings) templateChain(name string) ([]map[string]any, error) {
+	var chain []map[string]any
+	var names []string
+
+	for name != "" {
+		if slices.Contains(names, name) {
+			return nil, fmt.Errorf("template %q extends itself through %s", name, strings.Join(names, " -> "))
+		}
+		names = append(names, name)
+
+		template, ok := lookup(s.templates, name)
+		if !ok {
+			return nil, fmt.Errorf("unknown template %q", name)
+		}
+
+		chain = append(chain, template)
+		name, _ = template["extends"].(string)
+	}
+
+	return chain, nil
+}
+
+// resolve merges the shared settings into a raw monitor. The merged monitor
+// is returned along with the error when its template cannot be resolved, the
+// template is then ignored.
+func (s monitorSettings) resolve(monitor map[string]any) (map[string]any, error) {
+	extends, _ := monitor["extends"].(string)
+	chain, err := s.templateChain(extends)
+
+	specific := map[string]any{}
+	for i := len(chain) - 1; i >= 0; i-- {
+		mergeSettings(specific, chain[i])
+	}
+	mergeSettings(specific, monitor)
+
+	resolved := maps.Clone(s.defaults)
+	if resolved == nil {
+		resolved = map[string]any{}
+	}
+
+	// A template may set the group
+	if name, ok := specific["group"].(string); ok {
+		group, _ := lookup(s.groups, name)
+		group = maps.Clone(group)
+		delete(group, "notify")
+		mergeSettings(resolved, group)
+	}
+
+	mergeSettings(resolved, specific)
+	delete(resolved, "extends")
+
+	return resolved, err
+}
+
+// mergeSettings copies the settings of src into dst. Labels are merged, so
+// a monitor adds labels to the ones it inherits instead of replacing them.
+func mergeSettings(dst map[string]any, src map[string]any) {
+	for key, value := range src {
+		inherited, isMap := dst[key].(map[string]any)
+		labels, ok := value.(map[string]any)
+		if key == "labels" && isMap && ok {
+			value = maps.Clone(inherited)
+			maps.Copy(value.(map[string]any), labels)
+		}
+		dst[key] = value
+	}
+}
+
+// monitors resolves and decodes the raw monitors of the file at path.
+func (s monitorSettings) monitors(raw any, path string) ([]MonitorConfig, error) {
+	list, _ := raw.([]any)
+
+	var monitors []MonitorConfig
+	for i, item := range list {
+		rawMonitor, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: monitor[%d] is not a mapping", path, i)
+		}
+
+		resolved, err := s.resolve(rawMonitor)
+		if err != nil {
+			log.Warn().Err(err).Str("path", path).Msgf("ignoring the template of monitor[%d]", i)
+		}
+
+		monitor, err := decodeMonitor(resolved)
+		if err != nil {
+			return nil, fmt.Errorf("%s: monitor[%d]: %w", path, i, err)
+		}
+		monitors = append(monitors, monitor)
+	}
+
+	return monitors, nil
+}
+
+// lookup returns the mapping named name. Names are compared case-insensitively
+// as viper lowercases keys.
+func lookup(mapping map[string]any, name string) (map[string]any, bool) {
+	for key, value := range mapping {
+		if strings.EqualFold(key, name) {
+			value, ok := value.(map[string]any)
+			return value, ok
+		}
+	}
+
+	return nil, false
+}
+
+// decodeMonitor decodes a raw monitor like Load does, converting strings such
+// as resolved references to the type of the field.
+func decodeMonitor(raw map[string]any) (MonitorConfig, error) {
+	v := viper.New()
+	v.Set("monitor", raw)
+
+	var monitor MonitorConfig
+	err := v.UnmarshalKey("monitor", &monitor)
+	return monitor, err
+}
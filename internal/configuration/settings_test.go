@@ -0,0 +1,162 @@
+package configuration
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func parseSettings(t *testing.T, content string) monitorSettings {
+	t.Helper()
+
+	var raw rawConfig
+	require.NoError(t, yaml.Unmarshal([]byte(content), &raw))
+	return raw.settings()
+}
+
+func TestResolveMonitorSettings(t *testing.T) {
+	settings := parseSettings(t, `
+defaults:
+  interval: 1m
+  response_time_threshold: 10s
+  certificate_monitoring: true
+  labels:
+    env: prod
+groups:
+  Frontend:
+    interval: 2m
+    public: true
+    labels:
+      team: web
+    notify:
+      tags: [team-web]
+templates:
+  api:
+    extends: base
+    degraded_threshold: 2s
+  base:
+    interval: 30s
+    group: frontend
+  loop:
+    extends: loop
+`)
+
+	resolved, err := settings.resolve(map[string]any{"url": "https://example.com", "group": "frontend"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"url":                     "https://example.com",
+		"group":                   "frontend",
+		"interval":                "2m",
+		"response_time_threshold": "10s",
+		"certificate_monitoring":  true,
+		"public":                  true,
+		"labels":                  map[string]any{"env": "prod", "team": "web"},
+	}, resolved)
+
+	// Templates override the group they set, the monitor overrides both.
+	// Labels are merged.
+	resolved, err = settings.resolve(map[string]any{"url": "https://api.example.com", "extends": "api", "certificate_monitoring": false,
+		"labels": map[string]any{"env": "staging", "service": "api"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"url":                     "https://api.example.com",
+		"group":                   "frontend",
+		"interval":                "30s",
+		"response_time_threshold": "10s",
+		"certificate_monitoring":  false,
+		"public":                  true,
+		"degraded_threshold":      "2s",
+		"labels":                  map[string]any{"env": "staging", "team": "web", "service": "api"},
+	}, resolved)
+
+	_, err = settings.resolve(map[string]any{"extends": "missing"})
+	assert.EqualError(t, err, `unknown template "missing"`)
+
+	_, err = settings.resolve(map[string]any{"extends": "loop"})
+	assert.EqualError(t, err, `template "loop" extends itself through loop`)
+}
+
+func TestDecodeMonitor(t *testing.T) {
+	monitor, err := decodeMonitor(map[string]any{"url": "https://example.com", "enabled": "true", "degraded_average": "5"})
+	require.NoError(t, err)
+	assert.Equal(t, MonitorConfig{URL: "https://example.com", Enabled: true, DegradedAverage: 5}, monitor)
+
+	_, err = decodeMonitor(map[string]any{"enabled": "maybe"})
+	assert.Error(t, err)
+}
+
+func TestValidateFileSettings(t *testing.T) {
+	configPath := writeConfig(t, `defaults:
+  url: https://default.example.com
+  response_time_threshold: 10m
+templates:
+  api:
+    extends: missing
+    interval: 1h
+groups:
+  web:
+    colour: blue
+monitor:
+  - url: https://a.example.com
+  - url: https://b.example.com
+    extends: api
+`)
+
+	problems, err := ValidateFile(configPath)
+	require.NoError(t, err)
+
+	var fields []string
+	for _, problem := range problems {
+		fields = append(fields, problem.Field)
+	}
+	assert.Equal(t, []string{
+		"defaults.url",
+		"templates.api.extends",
+		"groups.web.colour",
+		"monitor[0].response_time_threshold",
+		"monitor[1].response_time_threshold",
+		"monitor[1].extends",
+	}, fields)
+	assert.Equal(t, "10m0s is not below interval 5m0s", problems[3].Message)
+	assert.Equal(t, 12, problems[3].Line, "inherited settings are reported on the monitor")
+}
+
+func TestUpdateConfigKeepsInheritedSettings(t *testing.T) {
+	configPath := writeConfig(t, `defaults:
+  response_time_threshold: 10s
+templates:
+  api:
+    interval: 1m
+monitor:
+  - url: https://a.example.com
+    extends: api
+`)
+
+	require.NoError(t, UpdateConfig(configPath, UpdateConfigRequest{Monitor: []MonitorConfig{
+		{URL: "https://a.example.com", Enabled: true, Interval: "60s", ResponseTimeThreshold: "10s"},
+		{URL: "https://b.example.com", Enabled: true, Interval: "2m", ResponseTimeThreshold: "10s"},
+	}}))
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, `defaults:
+  response_time_threshold: 10s
+templates:
+  api:
+    interval: 1m
+monitor:
+  - url: https://a.example.com
+    extends: api
+    enabled: true
+    certificate_monitoring: false
+    certificate_expired_before: ""
+  - url: https://b.example.com
+    enabled: true
+    interval: 2m
+    certificate_monitoring: false
+    certificate_expired_before: ""
+`, string(content))
+}
@@ -0,0 +1,539 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"uptime-go/internal/helper"
+	"uptime-go/internal/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError is a problem found in the configuration. Field is the path
+// of the offending value, e.g. "monitor[0].interval". File, Line and Column
+// are only set when a configuration file is validated.
+type ValidationError struct {
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	message := e.Message
+	if e.Field != "" {
+		message = e.Field + ": " + message
+	}
+
+	location := e.File
+	if e.Line > 0 {
+		location += ":" + strconv.Itoa(e.Line)
+		if e.Column > 0 {
+			location += ":" + strconv.Itoa(e.Column)
+		}
+	}
+	if location != "" {
+		message = strings.TrimPrefix(location, ":") + ": " + message
+	}
+
+	return message
+}
+
+var yamlErrorPattern = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// monitorIDPattern matches the ids assigned to monitors, which are used in
+// API paths
+var monitorIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// labelNamePattern matches the label names accepted by Prometheus
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ValidateFile strictly validates a configuration file and the files it
+// includes. Unlike Load, which falls back to defaults, it reports every
+// problem with its position: syntax errors, unknown fields, values of the
+// wrong type, invalid durations, duplicate URLs and thresholds that can
+// never be reached.
+func ValidateFile(configPath string) ([]ValidationError, error) {
+	main, problems, err := parseFile(configPath, reflect.TypeOf(fileConfig{}))
+	if err != nil || main == nil {
+		return problems, err
+	}
+
+	files := []*parsedFile{main}
+	paths, includeProblems := includedFiles(configPath, main.config.Include)
+	problems = append(problems, includeProblems...)
+	for _, path := range paths {
+		file, fileProblems, err := parseFile(path, reflect.TypeOf(includedConfig{}))
+		if err != nil {
+			return nil, err
+		}
+
+		problems = append(problems, fileProblems...)
+		if file != nil {
+			files = append(files, file)
+		}
+	}
+
+	// Monitors are validated together: URLs must be unique across files and
+	// dependencies may be defined in another file
+	type source struct {
+		file  string
+		index int
+	}
+	var monitors []MonitorConfig
+	var sources []source
+	settings := main.raw.settings()
+	for _, file := range files {
+		list, _ := file.raw.Monitor.([]any)
+		for i, item := range list {
+			raw, ok := item.(map[string]any)
+			if !ok || i >= len(file.config.Monitor) {
+				continue // Reported by checkNode
+			}
+
+			resolved, err := settings.resolve(raw)
+			if err != nil {
+				problems = append(problems, ValidationError{File: file.path, Field: fmt.Sprintf("monitor[%d].extends", i), Message: err.Error()})
+			}
+
+			monitor, err := decodeMonitor(resolved)
+			if err != nil {
+				monitor = file.config.Monitor[i].MonitorConfig // Type errors are reported by checkNode
+			}
+
+			monitors = append(monitors, monitor)
+			sources = append(sources, source{file.path, i})
+		}
+	}
+
+	problems = append(problems, validateSettings(main.path, settings)...)
+
+	if len(monitors) == 0 {
+		problems = append(problems, ValidationError{File: configPath, Field: "monitor", Message: "no monitors configured"})
+	}
+	problems = append(problems, validateMonitors(monitors, func(i int) (string, string) {
+		return sources[i].file, fmt.Sprintf("monitor[%d]", sources[i].index)
+	})...)
+
+	for _, problem := range validateNotifiers(main.config.Notifiers) {
+		problem.File = main.path
+		problems = append(problems, problem)
+	}
+
+	for _, file := range files {
+		for _, problem := range validateMaintenance(file.config.Maintenance) {
+			problem.File = file.path
+			problems = append(problems, problem)
+		}
+	}
+
+	order := map[string]int{}
+	for i, path := range append([]string{configPath}, paths...) {
+		order[path] = i
+	}
+
+	for i := range problems {
+		if problems[i].Line > 0 {
+			continue
+		}
+		for _, file := range files {
+			if file.path == problems[i].File {
+				node := closestNode(file.nodes, problems[i].Field)
+				problems[i].Line, problems[i].Column = node.Line, node.Column
+			}
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		a, b := problems[i], problems[j]
+		if a.File != b.File {
+			return order[a.File] < order[b.File]
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	return problems, nil
+}
+
+// parsedFile is a configuration file with the node of every field path.
+type parsedFile struct {
+	path   string
+	config fileConfig
+	raw    rawConfig
+	nodes  map[string]*yaml.Node
+}
+
+// parseFile decodes the configuration file at path, reporting syntax errors,
+// unresolved references, unknown fields and type errors against t. The file
+// is nil when it cannot be parsed.
+func parseFile(path string, t reflect.Type) (*parsedFile, []ValidationError, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading configuration: %w", err)
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		problem := ValidationError{File: path, Message: strings.TrimPrefix(err.Error(), "yaml: ")}
+		if match := yamlErrorPattern.FindStringSubmatch(err.Error()); match != nil {
+			problem.Line, _ = strconv.Atoi(match[1])
+			problem.Message = match[2]
+		}
+		return nil, []ValidationError{problem}, nil
+	}
+
+	file := &parsedFile{path: path, nodes: map[string]*yaml.Node{"": {Line: 1, Column: 1}}}
+	if len(document.Content) == 0 {
+		return file, nil, nil
+	}
+
+	problems := interpolateNode(&document)
+
+	root := document.Content[0]
+	file.nodes[""] = root
+	problems = append(problems, checkNode(root, t, "", file.nodes)...)
+
+	// Type errors are already reported by checkNode, the rest is decoded
+	for _, config := range []any{&file.config, &file.raw} {
+		if err := root.Decode(config); err != nil {
+			var typeError *yaml.TypeError
+			if !errors.As(err, &typeError) {
+				return nil, nil, err
+			}
+		}
+	}
+
+	for i := range problems {
+		problems[i].File = path
+	}
+
+	return file, problems, nil
+}
+
+// checkNode reports unknown fields and values that cannot be decoded into t,
+// and records the node of every field path in nodes.
+func checkNode(node *yaml.Node, t reflect.Type, path string, nodes map[string]*yaml.Node) []ValidationError {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	problem := func(node *yaml.Node, format string, args ...any) []ValidationError {
+		return []ValidationError{{Line: node.Line, Column: node.Column, Field: path, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil
+	}
+
+	var problems []ValidationError
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return problem(node, "expected a mapping")
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" {
+				continue // Merge keys are checked where the anchor is defined
+			}
+
+			fieldPath := key.Value
+			if path != "" {
+				fieldPath = path + "." + key.Value
+			}
+
+			field, ok := yamlField(t, key.Value)
+			if !ok {
+				problems = append(problems, ValidationError{Line: key.Line, Column: key.Column, Field: fieldPath, Message: "unknown field"})
+				continue
+			}
+
+			nodes[fieldPath] = value
+			problems = append(problems, checkNode(value, field.Type, fieldPath, nodes)...)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return problem(node, "expected a mapping")
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			valuePath := key.Value
+			if path != "" {
+				valuePath = path + "." + key.Value
+			}
+
+			nodes[valuePath] = value
+			problems = append(problems, checkNode(value, t.Elem(), valuePath, nodes)...)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return problem(node, "expected a list")
+		}
+
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			nodes[itemPath] = item
+			problems = append(problems, checkNode(item, t.Elem(), itemPath, nodes)...)
+		}
+	default:
+		if node.Kind != yaml.ScalarNode {
+			return problem(node, "expected a %s value", t.Kind())
+		}
+		// Resolved references are tagged as strings, infer their type like
+		// for any other plain value
+		scalar := *node
+		if scalar.Style == 0 {
+			scalar.Tag = ""
+		}
+		if err := scalar.Decode(reflect.New(t).Interface()); err != nil {
+			return problem(node, "invalid value %q, expected a %s", node.Value, t.Kind())
+		}
+	}
+
+	return problems
+}
+
+// yamlField returns the field of t decoded from key, which is the yaml tag
+// or the lowercased field name.
+func yamlField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if options == "inline" {
+			continue // Its fields are visible fields of t
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		if name == key {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+// closestNode returns the node of the field path, or of its closest parent
+// when the field is not set, e.g. a missing url.
+func closestNode(nodes map[string]*yaml.Node, path string) *yaml.Node {
+	for {
+		if node, ok := nodes[path]; ok {
+			return node
+		}
+
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			return nodes[""]
+		}
+		path = path[:i]
+	}
+}
+
+// Validate reports the problems that the typed request validation cannot
+// catch, such as duplicate URLs or unknown dependencies.
+func (r UpdateConfigRequest) Validate() []ValidationError {
+	problems := validateMonitors(r.Monitor, func(i int) (string, string) {
+		return "", fmt.Sprintf("monitor[%d]", i)
+	})
+
+	return append(problems, validateMaintenance(r.Maintenance)...)
+}
+
+// validateSettings reports templates that cannot be resolved and URLs in
+// settings shared by several monitors.
+func validateSettings(path string, settings monitorSettings) []ValidationError {
+	var problems []ValidationError
+	add := func(field string, message string) {
+		problems = append(problems, ValidationError{File: path, Field: field, Message: message})
+	}
+
+	if _, ok := settings.defaults["url"]; ok {
+		add("defaults.url", "is set per monitor")
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(settings.templates)) {
+		template, _ := settings.templates[name].(map[string]any)
+		if _, ok := template["url"]; ok {
+			add("templates."+name+".url", "is set per monitor")
+		}
+		if _, err := settings.templateChain(name); err != nil {
+			add("templates."+name+".extends", err.Error())
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(settings.groups)) {
+		group, _ := settings.groups[name].(map[string]any)
+		if _, ok := group["url"]; ok {
+			add("groups."+name+".url", "is set per monitor")
+		}
+	}
+
+	return problems
+}
+
+func validateMaintenance(windows []MaintenanceConfig) []ValidationError {
+	var problems []ValidationError
+	for i, raw := range windows {
+		if _, err := raw.Parse(); err != nil {
+			problems = append(problems, ValidationError{Field: fmt.Sprintf("maintenance[%d]", i), Message: err.Error()})
+		}
+
+		for j, tag := range raw.Tags {
+			if strings.TrimSpace(tag) == "" {
+				problems = append(problems, ValidationError{Field: fmt.Sprintf("maintenance[%d].tags[%d]", i, j), Message: "is empty"})
+			}
+		}
+	}
+
+	return problems
+}
+
+// validateNotifiers reports notifiers without a name or an http(s) URL, and
+// duplicate names.
+func validateNotifiers(notifiers []NotifierConfig) []ValidationError {
+	var problems []ValidationError
+	names := map[string]bool{}
+	for i, notifier := range notifiers {
+		field := fmt.Sprintf("notifiers[%d]", i)
+
+		switch {
+		case notifier.Name == "":
+			problems = append(problems, ValidationError{Field: field + ".name", Message: "is required"})
+		case names[notifier.Name]:
+			problems = append(problems, ValidationError{Field: field + ".name", Message: fmt.Sprintf("duplicate notifier %q", notifier.Name)})
+		}
+		names[notifier.Name] = true
+
+		if parsed, err := url.Parse(notifier.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			problems = append(problems, ValidationError{Field: field + ".url", Message: fmt.Sprintf("invalid URL %q, expected http(s)://host/path", notifier.URL)})
+		}
+	}
+
+	return problems
+}
+
+// validateMonitors checks the monitors together. location returns the file
+// and field path of the monitor at index i, e.g. "monitor[0]".
+func validateMonitors(monitors []MonitorConfig, location func(i int) (string, string)) []ValidationError {
+	var problems []ValidationError
+	add := func(i int, field string, format string, args ...any) {
+		file, path := location(i)
+		problems = append(problems, ValidationError{
+			File:    file,
+			Field:   path + "." + field,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	// Monitors sharing a URL are told apart by their id
+	refs := map[string]bool{} // IDs and URLs the monitors can be referred to by
+	ids := map[string]int{}
+	for i, monitor := range monitors {
+		if monitor.URL == "" {
+			continue
+		}
+		URL := helper.NormalizeURL(monitor.URL)
+		refs[URL] = true
+		refs[monitor.MonitorID()] = true
+
+		if monitor.ID != "" && !monitorIDPattern.MatchString(monitor.ID) {
+			add(i, "id", "invalid id %q, expected letters, digits, '.', '_' and '-'", monitor.ID)
+			continue
+		}
+
+		id := monitor.MonitorID()
+		if first, exists := ids[id]; exists {
+			file, _ := location(i)
+			firstFile, firstPath := location(first)
+			if firstFile != file {
+				firstPath += " in " + firstFile
+			}
+
+			if monitor.ID != "" {
+				add(i, "id", "duplicate of %s (%s)", firstPath, id)
+			} else {
+				add(i, "url", "duplicate of %s (%s), set an id to monitor it twice", firstPath, URL)
+			}
+			continue
+		}
+		ids[id] = i
+	}
+
+	for i, monitor := range monitors {
+		if monitor.URL == "" {
+			add(i, "url", "is required")
+			continue
+		}
+
+		validDurations := true
+		for _, duration := range []struct{ field, value string }{
+			{"interval", monitor.Interval},
+			{"response_time_threshold", monitor.ResponseTimeThreshold},
+			{"certificate_expired_before", monitor.CertificateExpiredBefore},
+			{"degraded_threshold", monitor.DegradedThreshold},
+		} {
+			if duration.value != "" && !helper.IsValidDuration(duration.value) {
+				add(i, duration.field, "invalid duration %q, expected e.g. 30s, 5m, 1h30m or 31d", duration.value)
+				validDurations = false
+			}
+		}
+
+		// Thresholds are only compared once the durations are valid, instead
+		// of against the defaults they would fall back to
+		if validDurations {
+			parsed := monitor.Parse()
+			if parsed.ResponseTimeThreshold >= parsed.Interval {
+				add(i, "response_time_threshold", "%s is not below interval %s", parsed.ResponseTimeThreshold, parsed.Interval)
+			}
+			if parsed.DegradedThreshold > 0 && parsed.DegradedThreshold >= parsed.ResponseTimeThreshold {
+				add(i, "degraded_threshold", "%s is not below response_time_threshold %s", parsed.DegradedThreshold, parsed.ResponseTimeThreshold)
+			}
+		}
+
+		self := models.Monitor{ID: monitor.MonitorID(), URL: helper.NormalizeURL(monitor.URL)}
+		for j, parent := range monitor.DependsOn {
+			ref := helper.NormalizeMonitorRef(parent)
+			switch known := refs[ref] || refs[helper.NormalizeURL(ref)]; {
+			case self.Matches(ref):
+				add(i, fmt.Sprintf("depends_on[%d]", j), "depends on itself")
+			case !known:
+				add(i, fmt.Sprintf("depends_on[%d]", j), "%s is not a configured monitor", ref)
+			}
+		}
+
+		for j, tag := range monitor.Tags {
+			if strings.TrimSpace(tag) == "" {
+				add(i, fmt.Sprintf("tags[%d]", j), "is empty")
+			}
+		}
+
+		for name := range monitor.Labels {
+			if !labelNamePattern.MatchString(name) || strings.HasPrefix(name, "__") {
+				add(i, "labels."+name, "invalid label name, expected letters, digits and underscores not starting with a digit or __")
+			}
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,153 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	return configPath
+}
+
+func TestValidateFile(t *testing.T) {
+	configPath := writeConfig(t, `monitor:
+  - url: "https://a.example.com"
+    enabled: yes please
+    interval: 5 minutes
+    retries: 3
+  - url: "https://A.example.com/"
+    interval: 1m
+    response_time_threshold: 1m
+    tags: [""]
+    labels:
+      9team: web
+  - name: missing url
+status_page:
+  titel: Status
+maintenance:
+  - name: deploy
+    weekdays: [someday]
+    from: "22:00"
+    tags: [" "]
+`)
+
+	problems, err := ValidateFile(configPath)
+	require.NoError(t, err)
+
+	type position struct {
+		Line   int
+		Column int
+		Field  string
+	}
+	var positions []position
+	for _, problem := range problems {
+		assert.Equal(t, configPath, problem.File)
+		positions = append(positions, position{problem.Line, problem.Column, problem.Field})
+	}
+
+	assert.Equal(t, []position{
+		{3, 14, "monitor[0].enabled"},
+		{4, 15, "monitor[0].interval"},
+		{5, 5, "monitor[0].retries"},
+		{6, 10, "monitor[1].url"},
+		{8, 30, "monitor[1].response_time_threshold"},
+		{9, 12, "monitor[1].tags[0]"},
+		{11, 14, "monitor[1].labels.9team"},
+		{12, 5, "monitor[2].url"},
+		{14, 3, "status_page.titel"},
+		{16, 5, "maintenance[0]"},
+		{19, 12, "maintenance[0].tags[0]"},
+	}, positions)
+
+	assert.Equal(t, configPath+":5:5: monitor[0].retries: unknown field", problems[2].Error())
+}
+
+func TestValidateFileValid(t *testing.T) {
+	problems, err := ValidateFile("../../configs/uptime.yml")
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+}
+
+func TestValidateFileSyntaxError(t *testing.T) {
+	configPath := writeConfig(t, "monitor:\n  - url: a\n    interval: [\n")
+
+	problems, err := ValidateFile(configPath)
+	require.NoError(t, err)
+	require.Len(t, problems, 1)
+	assert.Positive(t, problems[0].Line)
+	assert.NotEmpty(t, problems[0].Message)
+}
+
+func TestValidateFileEmpty(t *testing.T) {
+	problems, err := ValidateFile(writeConfig(t, ""))
+	require.NoError(t, err)
+	require.Len(t, problems, 1)
+	assert.Equal(t, "monitor", problems[0].Field)
+
+	_, err = ValidateFile(filepath.Join(t.TempDir(), "missing.yml"))
+	assert.Error(t, err)
+}
+
+func TestValidateFileNotifiers(t *testing.T) {
+	configPath := writeConfig(t, `monitor:
+  - url: https://example.com
+notifiers:
+  - name: ops
+    url: https://hooks.example.com/uptime
+  - name: ops
+    url: hooks.example.com
+  - url: https://hooks.example.com/other
+    token: secret
+`)
+
+	problems, err := ValidateFile(configPath)
+	require.NoError(t, err)
+
+	var messages []string
+	for _, problem := range problems {
+		messages = append(messages, problem.Field+": "+problem.Message)
+	}
+	assert.Equal(t, []string{
+		`notifiers[1].name: duplicate notifier "ops"`,
+		`notifiers[1].url: invalid URL "hooks.example.com", expected http(s)://host/path`,
+		"notifiers[2].name: is required",
+		"notifiers[2].token: unknown field",
+	}, messages)
+}
+
+func TestValidateFileMonitorIDs(t *testing.T) {
+	configPath := writeConfig(t, `monitor:
+  - url: https://example.com
+    depends_on: [example-eu, https://example.com, missing]
+  - id: example-eu
+    url: https://example.com
+  - id: example-eu
+    url: https://example.com/health
+  - id: not/valid
+    url: https://example.org
+  - url: https://example.com/
+`)
+
+	problems, err := ValidateFile(configPath)
+	require.NoError(t, err)
+
+	var messages []string
+	for _, problem := range problems {
+		messages = append(messages, problem.Field+": "+problem.Message)
+	}
+	assert.Equal(t, []string{
+		"monitor[0].depends_on[1]: depends on itself",
+		"monitor[0].depends_on[2]: missing is not a configured monitor",
+		"monitor[2].id: duplicate of monitor[1] (example-eu)",
+		`monitor[3].id: invalid id "not/valid", expected letters, digits, '.', '_' and '-'`,
+		"monitor[4].url: duplicate of monitor[0] (https://example.com), set an id to monitor it twice",
+	}, messages)
+}
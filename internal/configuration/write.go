@@ -0,0 +1,458 @@
+package configuration
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+	"uptime-go/internal/helper"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Number of configuration backups kept next to the configuration file
+const MaxConfigBackups = 10
+
+const backupTimeFormat = "20060102T150405.000000"
+
+var ErrNoBackup = errors.New("no configuration backup found")
+
+// InvalidConfigError is returned when a configuration is rejected before it
+// is written.
+type InvalidConfigError struct {
+	Problems []ValidationError
+}
+
+func (e *InvalidConfigError) Error() string {
+	messages := make([]string, 0, len(e.Problems))
+	for _, problem := range e.Problems {
+		messages = append(messages, problem.Error())
+	}
+
+	return "invalid configuration: " + strings.Join(messages, "; ")
+}
+
+type Backup struct {
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UpdateConfig validates the configuration and writes it in place of the
+// current one. Comments and keys unknown to uptime-go are preserved, the
+// previous file is kept as a timestamped backup and the file is replaced
+// atomically. Sections left nil are not changed, nor are monitors and
+// maintenance windows defined in included files.
+func UpdateConfig(configPath string, config UpdateConfigRequest) error {
+	if problems := config.Validate(); len(problems) > 0 {
+		return &InvalidConfigError{Problems: problems}
+	}
+
+	current, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error reading configuration: %w", err)
+	}
+
+	config, problems, err := withoutIncluded(configPath, current, config)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return &InvalidConfigError{Problems: problems}
+	}
+
+	updated, err := mergeConfig(current, config)
+	if err != nil {
+		return err
+	}
+
+	if len(current) > 0 {
+		if _, err := backupConfig(configPath, current); err != nil {
+			return err
+		}
+	}
+
+	return writeFileAtomic(configPath, updated)
+}
+
+// ListBackups returns the backups of a configuration file, newest first.
+func ListBackups(configPath string) ([]Backup, error) {
+	pattern := filepath.Join(filepath.Dir(configPath), filepath.Base(configPath)+".*.bak")
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	backups := []Backup{}
+	for _, path := range paths {
+		name := filepath.Base(path)
+		timestamp := strings.TrimSuffix(strings.TrimPrefix(name, filepath.Base(configPath)+"."), ".bak")
+		createdAt, err := time.ParseInLocation(backupTimeFormat, timestamp, time.UTC)
+		if err != nil {
+			continue // Not one of ours
+		}
+
+		backups = append(backups, Backup{Name: name, Path: path, CreatedAt: createdAt})
+	}
+
+	slices.SortFunc(backups, func(a, b Backup) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return backups, nil
+}
+
+// Rollback restores a backup, the latest one when name is empty. The
+// configuration being replaced is backed up first, so a rollback can be
+// undone.
+func Rollback(configPath string, name string) (*Backup, error) {
+	backups, err := ListBackups(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	index := 0
+	if name != "" {
+		index = slices.IndexFunc(backups, func(b Backup) bool { return b.Name == name })
+	}
+	if index < 0 || index >= len(backups) {
+		return nil, ErrNoBackup
+	}
+	backup := backups[index]
+
+	content, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading backup: %w", err)
+	}
+
+	var parsed UpdateConfigRequest
+	if err := yaml.Unmarshal(content, &parsed); err != nil {
+		return nil, fmt.Errorf("backup %s is not valid YAML: %w", backup.Name, err)
+	}
+
+	current, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading configuration: %w", err)
+	}
+
+	if len(current) > 0 {
+		if _, err := backupConfig(configPath, current); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeFileAtomic(configPath, content); err != nil {
+		return nil, err
+	}
+
+	return &backup, nil
+}
+
+func backupConfig(configPath string, content []byte) (string, error) {
+	name := fmt.Sprintf("%s.%s.bak", filepath.Base(configPath), time.Now().UTC().Format(backupTimeFormat))
+	path := filepath.Join(filepath.Dir(configPath), name)
+
+	if err := writeFileAtomic(path, content); err != nil {
+		return "", fmt.Errorf("error writing backup: %w", err)
+	}
+
+	backups, err := ListBackups(configPath)
+	if err != nil {
+		return path, nil
+	}
+
+	for _, old := range backups[min(len(backups), MaxConfigBackups):] {
+		os.Remove(old.Path)
+	}
+
+	return path, nil
+}
+
+// writeFileAtomic writes to a temporary file in the same directory and
+// renames it over path, so readers never see a partially written file.
+func writeFileAtomic(path string, content []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(content); err != nil {
+		temp.Close()
+		return fmt.Errorf("error writing temporary file: %w", err)
+	}
+
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return fmt.Errorf("error syncing temporary file: %w", err)
+	}
+
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("error closing temporary file: %w", err)
+	}
+
+	if err := os.Chmod(temp.Name(), mode); err != nil {
+		return fmt.Errorf("error setting file mode: %w", err)
+	}
+
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return fmt.Errorf("error replacing %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// mergeConfig applies the configuration to the YAML document in current,
+// editing nodes in place. Monitors are matched by URL and maintenance
+// windows by name, so comments attached to them survive the update.
+func mergeConfig(current []byte, config UpdateConfigRequest) ([]byte, error) {
+	var document yaml.Node
+	if len(bytes.TrimSpace(current)) > 0 {
+		if err := yaml.Unmarshal(current, &document); err != nil {
+			return nil, fmt.Errorf("error parsing current configuration: %w", err)
+		}
+	}
+
+	if document.Kind == 0 {
+		document = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("current configuration is not a YAML mapping")
+	}
+
+	var raw rawConfig
+	if err := root.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("error parsing current configuration: %w", err)
+	}
+	settings := raw.settings()
+
+	monitorKeys := yamlKeys(reflect.TypeOf(MonitorConfig{}))
+	monitorID := func(node *yaml.Node) string {
+		return MonitorConfig{ID: mappingValue(node, "id"), URL: redactedValue(mappingValue(node, "url"))}.MonitorID()
+	}
+	if err := setSection(root, "monitor", config.Monitor, func(dst, src *yaml.Node) {
+		mergeSequence(dst, src, monitorID, monitorKeys, func(existing, item *yaml.Node) {
+			pruneInherited(settings, existing, item)
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	if config.Maintenance != nil {
+		maintenanceKeys := yamlKeys(reflect.TypeOf(MaintenanceConfig{}))
+		maintenanceName := func(node *yaml.Node) string {
+			return redactedValue(mappingValue(node, "name"))
+		}
+		if err := setSection(root, "maintenance", config.Maintenance, func(dst, src *yaml.Node) {
+			mergeSequence(dst, src, maintenanceName, maintenanceKeys, nil)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.StatusPage != nil {
+		statusPageKeys := yamlKeys(reflect.TypeOf(StatusPageConfig{}))
+		if err := setSection(root, "status_page", config.StatusPage, func(dst, src *yaml.Node) {
+			mergeMapping(dst, src, statusPageKeys)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, fmt.Errorf("error marshalling to YAML: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("error marshalling to YAML: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// setSection encodes value under key in root. When the key already holds a
+// node of the same kind, merge combines both; otherwise the node is replaced.
+func setSection(root *yaml.Node, key string, value any, merge func(dst, src *yaml.Node)) error {
+	var encoded yaml.Node
+	if err := encoded.Encode(value); err != nil {
+		return fmt.Errorf("error marshalling %s: %w", key, err)
+	}
+
+	index := mappingIndex(root, key)
+	if index < 0 {
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &encoded)
+		return nil
+	}
+
+	existing := root.Content[index+1]
+	if existing.Kind != encoded.Kind {
+		encoded.HeadComment = existing.HeadComment
+		root.Content[index+1] = &encoded
+		return nil
+	}
+
+	merge(existing, &encoded)
+	return nil
+}
+
+// mergeSequence replaces the items of dst with those of src. Items of dst
+// with the same identity as an item of src are updated in place. When set,
+// prepare is called with every item of src and the item of dst it updates,
+// if any.
+func mergeSequence(dst, src *yaml.Node, identity func(*yaml.Node) string, known []string, prepare func(existing, item *yaml.Node)) {
+	used := make([]bool, len(dst.Content))
+	items := make([]*yaml.Node, 0, len(src.Content))
+
+	for _, item := range src.Content {
+		match := -1
+		for i, existing := range dst.Content {
+			if !used[i] && existing.Kind == yaml.MappingNode && identity(existing) == identity(item) {
+				match = i
+				break
+			}
+		}
+
+		if match < 0 {
+			if prepare != nil {
+				prepare(nil, item)
+			}
+			items = append(items, item)
+			continue
+		}
+
+		if prepare != nil {
+			prepare(dst.Content[match], item)
+		}
+
+		used[match] = true
+		mergeMapping(dst.Content[match], item, known)
+		items = append(items, dst.Content[match])
+	}
+
+	dst.Content = items
+	dst.Style = src.Style
+}
+
+// mergeMapping sets the keys of src in dst and removes known keys missing
+// from src. Unknown keys, comments and references such as ${TOKEN} that
+// resolve to the new value are kept.
+func mergeMapping(dst, src *yaml.Node, known []string) {
+	present := map[string]bool{}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		present[key.Value] = true
+
+		index := mappingIndex(dst, key.Value)
+		if index < 0 {
+			dst.Content = append(dst.Content, key, value)
+			continue
+		}
+
+		previous := dst.Content[index+1]
+		if previous.Kind == yaml.ScalarNode && value.Kind == yaml.ScalarNode &&
+			hasReference(previous.Value) && redactedValue(previous.Value) == redactedValue(value.Value) {
+			continue // Keep the reference instead of writing its value
+		}
+
+		value.HeadComment = previous.HeadComment
+		value.LineComment = previous.LineComment
+		value.FootComment = previous.FootComment
+		if previous.Kind == yaml.ScalarNode && value.Kind == yaml.ScalarNode && previous.Value == value.Value {
+			value.Style = previous.Style // Keep quoting of unchanged values
+		}
+		dst.Content[index+1] = value
+	}
+
+	for i := 0; i+1 < len(dst.Content); {
+		key := dst.Content[i].Value
+		if slices.Contains(known, key) && !present[key] {
+			dst.Content = slices.Delete(dst.Content, i, i+2)
+			continue
+		}
+		i += 2
+	}
+}
+
+// pruneInherited removes the settings of a monitor that it inherits from the
+// defaults, its group or its template, unless the existing monitor sets them,
+// so changing the shared settings keeps changing the monitor.
+func pruneInherited(settings monitorSettings, existing, item *yaml.Node) {
+	own := map[string]any{"group": mappingValue(item, "group")}
+	if existing != nil {
+		own["extends"] = mappingValue(existing, "extends")
+	}
+
+	inherited, _ := settings.resolve(own)
+	for i := 0; i+1 < len(item.Content); {
+		key, value := item.Content[i].Value, item.Content[i+1]
+		setting, ok := inherited[key]
+		if ok && key != "group" && (existing == nil || mappingIndex(existing, key) < 0) && sameSetting(key, setting, value) {
+			item.Content = slices.Delete(item.Content, i, i+2)
+			continue
+		}
+		i += 2
+	}
+}
+
+// sameSetting reports whether the raw setting has the value of node.
+func sameSetting(key string, setting any, node *yaml.Node) bool {
+	if node.Kind != yaml.ScalarNode {
+		return false
+	}
+
+	value := redactedValue(fmt.Sprint(setting))
+	switch key {
+	case "interval", "response_time_threshold", "certificate_expired_before", "degraded_threshold":
+		return helper.ParseDuration(value, "") == helper.ParseDuration(node.Value, "")
+	default:
+		return value == redactedValue(node.Value)
+	}
+}
+
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func mappingValue(node *yaml.Node, key string) string {
+	if index := mappingIndex(node, key); index >= 0 {
+		return node.Content[index+1].Value
+	}
+
+	return ""
+}
+
+// yamlKeys returns the keys written for a struct, from its yaml tags.
+func yamlKeys(t reflect.Type) []string {
+	var keys []string
+	for _, field := range reflect.VisibleFields(t) {
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		if name != "-" {
+			keys = append(keys, name)
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,128 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const currentConfig = `# uptime-go configuration
+# durations: s/m/h/d
+
+monitor:
+  - url: "https://a.example.com"
+    # shown on the status page
+    name: A
+    enabled: true
+    interval: 5m # every five minutes
+    response_time_threshold: 10s
+    custom_key: kept
+  - url: "https://b.example.com"
+    enabled: true
+    interval: 5m
+    response_time_threshold: 10s
+
+unknown_section:
+  value: 1
+`
+
+func TestUpdateConfigPreservesCommentsAndUnknownKeys(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(currentConfig), 0600))
+
+	err := UpdateConfig(configPath, UpdateConfigRequest{
+		Monitor: []MonitorConfig{
+			{URL: "https://a.example.com/", Enabled: true, Interval: "1m", ResponseTimeThreshold: "10s"},
+			{URL: "https://c.example.com", Enabled: true, Interval: "5m", ResponseTimeThreshold: "10s"},
+		},
+	})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	updated := string(content)
+
+	assert.Contains(t, updated, "# uptime-go configuration")
+	assert.Contains(t, updated, "interval: 1m # every five minutes")
+	assert.Contains(t, updated, "custom_key: kept")
+	assert.Contains(t, updated, "unknown_section:")
+	assert.Contains(t, updated, "https://c.example.com")
+	assert.NotContains(t, updated, "https://b.example.com")
+	assert.NotContains(t, updated, "name: A")
+
+	info, err := os.Stat(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	backups, err := ListBackups(configPath)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+
+	backup, err := os.ReadFile(backups[0].Path)
+	require.NoError(t, err)
+	assert.Equal(t, currentConfig, string(backup))
+}
+
+func TestUpdateConfigRejectsInvalidConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(currentConfig), 0644))
+
+	err := UpdateConfig(configPath, UpdateConfigRequest{
+		Monitor: []MonitorConfig{{URL: "https://a.example.com", Interval: "5 minutes"}},
+	})
+
+	var invalid *InvalidConfigError
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "monitor[0].interval", invalid.Problems[0].Field)
+
+	content, _ := os.ReadFile(configPath)
+	assert.Equal(t, currentConfig, string(content))
+
+	backups, _ := ListBackups(configPath)
+	assert.Empty(t, backups)
+}
+
+func TestRollback(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(currentConfig), 0644))
+
+	for _, interval := range []string{"1m", "2m"} {
+		require.NoError(t, UpdateConfig(configPath, UpdateConfigRequest{
+			Monitor: []MonitorConfig{{URL: "https://a.example.com", Interval: interval, ResponseTimeThreshold: "10s"}},
+		}))
+	}
+
+	restored, err := Rollback(configPath, "")
+	require.NoError(t, err)
+	content, _ := os.ReadFile(configPath)
+	assert.Contains(t, string(content), "interval: 1m")
+
+	backups, _ := ListBackups(configPath)
+	assert.Len(t, backups, 3)
+
+	_, err = Rollback(configPath, backups[len(backups)-1].Name)
+	require.NoError(t, err)
+	content, _ = os.ReadFile(configPath)
+	assert.Equal(t, currentConfig, string(content))
+	assert.NotEqual(t, restored.Name, backups[len(backups)-1].Name)
+
+	_, err = Rollback(configPath, "missing.bak")
+	assert.ErrorIs(t, err, ErrNoBackup)
+}
+
+func TestBackupsArePruned(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(currentConfig), 0644))
+
+	for i := 0; i < MaxConfigBackups+3; i++ {
+		require.NoError(t, UpdateConfig(configPath, UpdateConfigRequest{
+			Monitor: []MonitorConfig{{URL: "https://a.example.com", Interval: "1m", ResponseTimeThreshold: "10s"}},
+		}))
+	}
+
+	backups, _ := ListBackups(configPath)
+	assert.Len(t, backups, MaxConfigBackups)
+}
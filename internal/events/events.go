@@ -0,0 +1,121 @@
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+)
+
+const (
+	CheckCompleted   = "check"
+	IncidentOpened   = "incident.opened"
+	IncidentUpdated  = "incident.updated"
+	IncidentResolved = "incident.resolved"
+)
+
+// Number of events buffered per subscriber before events are dropped
+const subscriberBuffer = 64
+
+type Event struct {
+	ID   uint64    `json:"id"`
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	Data any       `json:"data"`
+}
+
+// Check is the result of a single check and the payload of a
+// CheckCompleted event.
+type Check struct {
+	MonitorID              string        `json:"monitor_id,omitempty"`
+	URL                    string        `json:"url"`
+	Name                   string        `json:"name,omitempty"`
+	Status                 string        `json:"status"`
+	IsUp                   bool          `json:"is_up"`
+	IsDegraded             bool          `json:"is_degraded"`
+	StatusCode             int           `json:"status_code"`
+	ResponseTime           int64         `json:"response_time"` // in milliseconds
+	FailureType            incident.Type `json:"failure_type,omitempty"`
+	InMaintenance          bool          `json:"in_maintenance,omitempty"`
+	Suppressed             bool          `json:"suppressed,omitempty"`
+	Error                  string        `json:"error,omitempty"`
+	CertificateExpiredDate *time.Time    `json:"certificate_expired_date,omitempty"`
+	CheckedAt              time.Time     `json:"checked_at"`
+}
+
+// IncidentChange is the payload of incident events.
+type IncidentChange struct {
+	Incident *models.Incident `json:"incident"`
+	Event    string           `json:"event"`
+	Message  string           `json:"message"`
+	Actor    string           `json:"actor"`
+}
+
+// Bus fans out events to subscribers. Publishing never blocks: events are
+// dropped for subscribers that do not keep up.
+type Bus struct {
+	mutex       sync.RWMutex
+	subscribers map[chan Event]struct{}
+	sequence    atomic.Uint64
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[chan Event]struct{})}
+}
+
+// Subscribe returns a channel receiving every published event and a function
+// to unsubscribe, which closes the channel.
+func (b *Bus) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mutex.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mutex.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mutex.Lock()
+			delete(b.subscribers, ch)
+			b.mutex.Unlock()
+			close(ch)
+		})
+	}
+}
+
+func (b *Bus) Publish(eventType string, data any) {
+	if b == nil {
+		return
+	}
+
+	event := Event{
+		ID:   b.sequence.Add(1),
+		Type: eventType,
+		Time: time.Now(),
+		Data: data,
+	}
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default: // Slow subscriber
+		}
+	}
+}
+
+// IncidentEventType maps an incident timeline event to the bus event type.
+func IncidentEventType(timelineEvent string) string {
+	switch timelineEvent {
+	case models.IncidentEventCreated:
+		return IncidentOpened
+	case models.IncidentEventResolved, models.IncidentEventFalsePositive:
+		return IncidentResolved
+	default:
+		return IncidentUpdated
+	}
+}
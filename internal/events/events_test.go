@@ -0,0 +1,42 @@
+package events
+
+import (
+	"testing"
+	"uptime-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus(t *testing.T) {
+	bus := NewBus()
+	first, unsubscribeFirst := bus.Subscribe()
+	second, unsubscribeSecond := bus.Subscribe()
+	defer unsubscribeSecond()
+
+	bus.Publish(CheckCompleted, Check{URL: "https://example.com"})
+
+	for _, ch := range []<-chan Event{first, second} {
+		event := <-ch
+		assert.Equal(t, uint64(1), event.ID)
+		assert.Equal(t, CheckCompleted, event.Type)
+		assert.Equal(t, "https://example.com", event.Data.(Check).URL)
+	}
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+	_, ok := <-first
+	assert.False(t, ok)
+
+	// Slow subscribers miss events instead of blocking the publisher
+	for i := 0; i < subscriberBuffer+10; i++ {
+		bus.Publish(CheckCompleted, Check{})
+	}
+	assert.Len(t, second, subscriberBuffer)
+}
+
+func TestIncidentEventType(t *testing.T) {
+	assert.Equal(t, IncidentOpened, IncidentEventType(models.IncidentEventCreated))
+	assert.Equal(t, IncidentResolved, IncidentEventType(models.IncidentEventResolved))
+	assert.Equal(t, IncidentResolved, IncidentEventType(models.IncidentEventFalsePositive))
+	assert.Equal(t, IncidentUpdated, IncidentEventType(models.IncidentEventAcknowledged))
+}
@@ -3,15 +3,34 @@ package helper
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"fmt"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 )
 
+// MonitorID returns the ID of a monitor that is not assigned one: a
+// name-based UUID of its normalized URL, so it is the same on every start.
+func MonitorID(url string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(url)).String()
+}
+
+// NormalizeMonitorRef normalizes a reference to a monitor, its ID or URL.
+// References with a scheme are URLs, others are kept as written as they may
+// be IDs.
+func NormalizeMonitorRef(ref string) string {
+	if strings.Contains(ref, "://") {
+		return NormalizeURL(ref)
+	}
+
+	return ref
+}
+
 func GenerateRandomID() string {
 	b := make([]byte, 4)
 	if _, err := rand.Read(b); err != nil {
@@ -22,13 +41,24 @@ func GenerateRandomID() string {
 	return hex.EncodeToString(b)
 }
 
+var durationPattern = regexp.MustCompile(`^(\d+[smhd])+$`)
+
+// IsValidDuration reports whether input is entirely made of duration
+// components accepted by ParseDuration, e.g. "90s", "1h30m" or "31d".
+func IsValidDuration(input string) bool {
+	return durationPattern.MatchString(input)
+}
+
 func ParseDuration(input string, defaultValue string) time.Duration {
 	re := regexp.MustCompile(`(\d+)([smhd])`)
 	matches := re.FindAllStringSubmatch(input, -1)
 
 	if len(matches) == 0 && defaultValue != "" {
-		log.Warn().Msgf("invalid duration string: '%s'", input)
-		log.Warn().Msgf("using default value: %s", defaultValue)
+		// An empty value is not set, only warn about invalid ones
+		if input != "" {
+			log.Warn().Msgf("invalid duration string: '%s'", input)
+			log.Warn().Msgf("using default value: %s", defaultValue)
+		}
 		return ParseDuration(defaultValue, "")
 	}
 
@@ -52,6 +82,27 @@ func ParseDuration(input string, defaultValue string) time.Duration {
 	return total
 }
 
+// FormatDuration is the inverse of ParseDuration, e.g. "31d" or "1h30m".
+// Sub-second precision is dropped.
+func FormatDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+
+	var result strings.Builder
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{{"d", 24 * time.Hour}, {"h", time.Hour}, {"m", time.Minute}, {"s", time.Second}} {
+		if count := d / unit.size; count > 0 {
+			fmt.Fprintf(&result, "%d%s", count, unit.suffix)
+			d -= count * unit.size
+		}
+	}
+
+	return result.String()
+}
+
 // NormalizeURL cleans and standardizes a URL string.
 // It adds a default HTTPS scheme if missing, removes trailing slashes,
 // and converts the host to lowercase.
@@ -13,6 +13,14 @@ func TestGenerateRandomID(t *testing.T) {
 	assert.Equal(t, len(result), 8)
 }
 
+func TestMonitorID(t *testing.T) {
+	id := MonitorID("https://example.com")
+
+	assert.Len(t, id, 36)
+	assert.Equal(t, id, MonitorID("https://example.com"))
+	assert.NotEqual(t, id, MonitorID("https://example.org"))
+}
+
 func TestParseDurationDays(t *testing.T) {
 	result := ParseDuration("19d", "1d")
 
@@ -30,3 +38,21 @@ func TestParseDurationDefault(t *testing.T) {
 
 	assert.Equal(t, result, time.Duration(19)*time.Second)
 }
+
+func TestIsValidDuration(t *testing.T) {
+	for _, input := range []string{"30s", "5m", "1h30m", "31d"} {
+		assert.True(t, IsValidDuration(input), input)
+	}
+
+	for _, input := range []string{"", "5", "5 minutes", "19M", "1h 30m", "-5m"} {
+		assert.False(t, IsValidDuration(input), input)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for input, expected := range map[string]string{"30s": "30s", "90s": "1m30s", "1h30m": "1h30m", "31d": "31d", "48h": "2d"} {
+		assert.Equal(t, expected, FormatDuration(ParseDuration(input, "")))
+	}
+
+	assert.Equal(t, "", FormatDuration(0))
+}
@@ -19,12 +19,40 @@ const (
 )
 
 const (
-	UnexpectedStatusCode Type = "unexpected_status_code"
-	SSLExpired           Type = "certificate_expired"
-	Timeout              Type = "timeout"
+	UnexpectedStatusCode  Type = "unexpected_status_code"
+	SSLExpired            Type = "certificate_expired"
+	Timeout               Type = "timeout"
+	DNSFailure            Type = "dns_failure"
+	ConnectionRefused     Type = "connection_refused"
+	ConnectionReset       Type = "connection_reset"
+	TLSHandshakeFailure   Type = "tls_handshake_failure"
+	CertificateInvalid    Type = "certificate_invalid"
+	Flapping              Type = "flapping"
+	Degraded              Type = "degraded"
+	PerformanceRegression Type = "performance_regression"
 )
 
+// WebsiteDownTypes lists the incident types opened when a website is down
+var WebsiteDownTypes = []Type{
+	UnexpectedStatusCode,
+	Timeout,
+	DNSFailure,
+	ConnectionRefused,
+	ConnectionReset,
+	TLSHandshakeFailure,
+	CertificateInvalid,
+}
+
+// SlowTypes lists the incident types opened when a website is up but slow
+var SlowTypes = []Type{
+	Degraded,
+	PerformanceRegression,
+}
+
 const (
-	EventWebsiteDown               string = "website_down"
-	EventWebsiteCertificateExpired string = "website_certificate_expired"
+	EventWebsiteDown                  string = "website_down"
+	EventWebsiteCertificateExpired    string = "website_certificate_expired"
+	EventWebsiteFlapping              string = "website_flapping"
+	EventWebsiteDegraded              string = "website_degraded"
+	EventWebsitePerformanceRegression string = "website_performance_regression"
 )
@@ -0,0 +1,188 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	MaintenanceSourceConfig = "config"
+	MaintenanceSourceAPI    = "api"
+)
+
+// Maintenance is a scheduled window during which checks keep running but
+// failures do not open incidents or send notifications.
+//
+// A window is either one-off (StartsAt/EndsAt) or recurring (Weekdays and a
+// From/To time of day in Timezone). StartsAt/EndsAt also bound a recurring
+// window when both are set. The window covers the monitors referred to in
+// Monitors, by ID or URL, and the monitors with one of Tags. It covers every
+// monitor when both are empty.
+type Maintenance struct {
+	ID          string     `json:"id" gorm:"primaryKey"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Source      string     `json:"source" gorm:"index"`
+	Monitors    []string   `json:"monitors,omitempty" gorm:"serializer:json"`
+	Tags        []string   `json:"tags,omitempty" gorm:"serializer:json"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	Weekdays    []string   `json:"weekdays,omitempty" gorm:"serializer:json"`
+	From        string     `json:"from,omitempty"`
+	To          string     `json:"to,omitempty"`
+	Timezone    string     `json:"timezone,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+func (m Maintenance) IsRecurring() bool {
+	return len(m.Weekdays) > 0 || m.From != "" || m.To != ""
+}
+
+// Validate reports the first problem that would make the window unusable.
+func (m Maintenance) Validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("maintenance window name is required")
+	}
+
+	if m.StartsAt != nil && m.EndsAt != nil && !m.EndsAt.After(*m.StartsAt) {
+		return fmt.Errorf("maintenance window %q: ends_at must be after starts_at", m.Name)
+	}
+
+	if !m.IsRecurring() {
+		if m.StartsAt == nil || m.EndsAt == nil {
+			return fmt.Errorf("maintenance window %q: one-off windows need both starts_at and ends_at", m.Name)
+		}
+		return nil
+	}
+
+	if _, err := m.location(); err != nil {
+		return fmt.Errorf("maintenance window %q: invalid timezone %q: %w", m.Name, m.Timezone, err)
+	}
+
+	if _, err := m.weekdaySet(); err != nil {
+		return fmt.Errorf("maintenance window %q: %w", m.Name, err)
+	}
+
+	if _, _, err := m.minutes(); err != nil {
+		return fmt.Errorf("maintenance window %q: %w", m.Name, err)
+	}
+
+	return nil
+}
+
+// AppliesTo reports whether the window covers the monitor.
+func (m Maintenance) AppliesTo(monitor Monitor) bool {
+	if len(m.Monitors) == 0 && len(m.Tags) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(m.Monitors, monitor.Matches) ||
+		slices.ContainsFunc(m.Tags, func(tag string) bool { return slices.Contains(monitor.Tags, tag) })
+}
+
+// IsActive reports whether t falls inside the window. Invalid windows are
+// never active.
+func (m Maintenance) IsActive(t time.Time) bool {
+	if m.StartsAt != nil && t.Before(*m.StartsAt) {
+		return false
+	}
+
+	if m.EndsAt != nil && !t.Before(*m.EndsAt) {
+		return false
+	}
+
+	if !m.IsRecurring() {
+		return m.StartsAt != nil && m.EndsAt != nil
+	}
+
+	loc, err := m.location()
+	if err != nil {
+		return false
+	}
+
+	days, err := m.weekdaySet()
+	if err != nil {
+		return false
+	}
+
+	from, to, err := m.minutes()
+	if err != nil {
+		return false
+	}
+
+	local := t.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	onDay := func(d time.Weekday) bool { return len(days) == 0 || days[d] }
+
+	if from < to {
+		return minute >= from && minute < to && onDay(local.Weekday())
+	}
+
+	// Window crosses midnight, e.g. 22:00-02:00
+	if minute >= from && onDay(local.Weekday()) {
+		return true
+	}
+
+	return minute < to && onDay(local.AddDate(0, 0, -1).Weekday())
+}
+
+func (m Maintenance) location() (*time.Location, error) {
+	if m.Timezone == "" {
+		return time.Local, nil
+	}
+
+	return time.LoadLocation(m.Timezone)
+}
+
+func (m Maintenance) weekdaySet() (map[time.Weekday]bool, error) {
+	set := make(map[time.Weekday]bool, len(m.Weekdays))
+	for _, day := range m.Weekdays {
+		weekday, ok := weekdays[strings.ToLower(strings.TrimSpace(day))]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", day)
+		}
+		set[weekday] = true
+	}
+
+	return set, nil
+}
+
+// minutes returns From and To as minutes since midnight. An empty From means
+// midnight and an empty To means end of day.
+func (m Maintenance) minutes() (int, int, error) {
+	from, to := 0, 24*60
+
+	if m.From != "" {
+		t, err := time.Parse("15:04", m.From)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid from time %q, expected HH:MM", m.From)
+		}
+		from = t.Hour()*60 + t.Minute()
+	}
+
+	if m.To != "" {
+		t, err := time.Parse("15:04", m.To)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid to time %q, expected HH:MM", m.To)
+		}
+		to = t.Hour()*60 + t.Minute()
+	}
+
+	if from == to {
+		return 0, 0, fmt.Errorf("from and to must differ")
+	}
+
+	return from, to, nil
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceIsActive(t *testing.T) {
+	start := time.Date(2025, 9, 1, 20, 0, 0, 0, time.UTC)
+	end := start.Add(3 * time.Hour)
+
+	testCases := []struct {
+		name     string
+		window   Maintenance
+		at       time.Time
+		expected bool
+	}{
+		{
+			name:     "one-off inside window",
+			window:   Maintenance{StartsAt: &start, EndsAt: &end},
+			at:       start.Add(time.Hour),
+			expected: true,
+		},
+		{
+			name:     "one-off after window",
+			window:   Maintenance{StartsAt: &start, EndsAt: &end},
+			at:       end,
+			expected: false,
+		},
+		{
+			// 2025-09-02 is a Tuesday
+			name:     "recurring on matching weekday",
+			window:   Maintenance{Weekdays: []string{"tue"}, From: "22:00", To: "23:30", Timezone: "UTC"},
+			at:       time.Date(2025, 9, 2, 22, 15, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "recurring on other weekday",
+			window:   Maintenance{Weekdays: []string{"tue"}, From: "22:00", To: "23:30", Timezone: "UTC"},
+			at:       time.Date(2025, 9, 3, 22, 15, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "recurring uses timezone",
+			window:   Maintenance{From: "22:00", To: "23:00", Timezone: "Asia/Jakarta"},
+			at:       time.Date(2025, 9, 2, 15, 30, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "recurring across midnight after midnight",
+			window:   Maintenance{Weekdays: []string{"monday"}, From: "23:00", To: "01:00", Timezone: "UTC"},
+			at:       time.Date(2025, 9, 2, 0, 30, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "invalid timezone is never active",
+			window:   Maintenance{From: "00:00", To: "23:59", Timezone: "Mars/Olympus"},
+			at:       time.Date(2025, 9, 2, 12, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.window.IsActive(tc.at))
+		})
+	}
+}
+
+func TestMaintenanceValidate(t *testing.T) {
+	start := time.Now()
+
+	assert.Error(t, Maintenance{Name: "missing end", StartsAt: &start}.Validate())
+	assert.Error(t, Maintenance{Name: "bad weekday", Weekdays: []string{"funday"}}.Validate())
+	assert.Error(t, Maintenance{Name: "bad time", From: "25:00"}.Validate())
+	assert.NoError(t, Maintenance{Name: "weekends", Weekdays: []string{"sat", "sun"}}.Validate())
+}
+
+func TestMaintenanceAppliesTo(t *testing.T) {
+	monitor := Monitor{ID: "example-eu", URL: "https://example.com", Tags: []string{"checkout", "eu"}}
+	sibling := Monitor{ID: "example-us", URL: "https://example.com", Tags: []string{"checkout"}}
+
+	testCases := []struct {
+		name     string
+		monitors []string
+		tags     []string
+		expected [2]bool
+	}{
+		{"every monitor", nil, nil, [2]bool{true, true}},
+		{"by URL", []string{"https://example.com/"}, nil, [2]bool{true, true}},
+		{"by host", []string{"example.com"}, nil, [2]bool{true, true}},
+		{"by ID", []string{"example-eu"}, nil, [2]bool{true, false}},
+		{"by tag", nil, []string{"eu"}, [2]bool{true, false}},
+		{"by ID or tag", []string{"example-us"}, []string{"eu"}, [2]bool{true, true}},
+		{"other", []string{"https://example.org", "example"}, []string{"us"}, [2]bool{false, false}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			window := Maintenance{Monitors: tc.monitors, Tags: tc.tags}
+			assert.Equal(t, tc.expected, [2]bool{window.AppliesTo(monitor), window.AppliesTo(sibling)})
+		})
+	}
+}
@@ -12,46 +12,93 @@ import (
 )
 
 type Monitor struct {
-	ID                       string           `json:"-" gorm:"primaryKey"`
-	URL                      string           `json:"url" gorm:"unique"`
-	Enabled                  bool             `json:"-"`
-	Interval                 time.Duration    `json:"-"`
-	ResponseTimeThreshold    time.Duration    `json:"-"`
-	CertificateMonitoring    bool             `json:"-"`
-	CertificateExpiredBefore *time.Duration   `json:"-"`
-	IsUp                     *bool            `json:"is_up"`
-	StatusCode               *int             `json:"status_code"`
-	ResponseTime             *int64           `json:"response_time"`
-	CertificateExpiredDate   *time.Time       `json:"certificate_expired_date"`
-	LastUp                   *time.Time       `json:"last_up"`
-	LastDown                 *time.Time       `json:"last_down"`
-	CreatedAt                time.Time        `json:"-"`
-	UpdatedAt                time.Time        `json:"last_check"`
-	Histories                []MonitorHistory `json:"histories,omitempty" gorm:"foreignKey:MonitorID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
-	Incidents                []Incident       `json:"-" gorm:"foreignKey:MonitorID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	ID                       string            `json:"id" gorm:"primaryKey"`
+	URL                      string            `json:"url" gorm:"index"`
+	Name                     string            `json:"name,omitempty"`
+	Group                    string            `json:"group,omitempty" gorm:"column:group_name;index"`
+	Public                   bool              `json:"-"`
+	Enabled                  bool              `json:"-"`
+	Interval                 time.Duration     `json:"-"`
+	ResponseTimeThreshold    time.Duration     `json:"-"`
+	CertificateMonitoring    bool              `json:"-"`
+	CertificateExpiredBefore *time.Duration    `json:"-"`
+	DependsOn                []string          `json:"depends_on,omitempty" gorm:"serializer:json"`
+	DegradedThreshold        time.Duration     `json:"-"`
+	DegradedAverage          int               `json:"-"` // number of checks averaged, 0 or 1 uses the last check only
+	AnomalyDetection         bool              `json:"-"`
+	Tags                     []string          `json:"tags,omitempty" gorm:"serializer:json"`
+	Labels                   map[string]string `json:"labels,omitempty" gorm:"serializer:json"`
+	IsUp                     *bool             `json:"is_up"`
+	IsDegraded               *bool             `json:"is_degraded"`
+	StatusCode               *int              `json:"status_code"`
+	ResponseTime             *int64            `json:"response_time"`
+	CertificateExpiredDate   *time.Time        `json:"certificate_expired_date"`
+	LastUp                   *time.Time        `json:"last_up"`
+	LastDown                 *time.Time        `json:"last_down"`
+	CreatedAt                time.Time         `json:"-"`
+	UpdatedAt                time.Time         `json:"last_check"`
+	Uptime                   *float64          `json:"uptime,omitempty" gorm:"-"`
+	Histories                []MonitorHistory  `json:"histories,omitempty" gorm:"foreignKey:MonitorID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Incidents                []Incident        `json:"-" gorm:"foreignKey:MonitorID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 }
 
 type MonitorHistory struct {
-	ID           string    `json:"-" gorm:"primaryKey"`
-	MonitorID    string    `json:"-"`
-	IsUp         bool      `json:"is_up" gorm:"index"`
-	StatusCode   int       `json:"-"`
-	ResponseTime int64     `json:"response_time"` // in milliseconds
-	CreatedAt    time.Time `json:"created_at" gorm:"index"`
-	Monitor      Monitor   `json:"-" gorm:"foreignKey:MonitorID"`
+	ID            string    `json:"-" gorm:"primaryKey"`
+	MonitorID     string    `json:"-"`
+	IsUp          bool      `json:"is_up" gorm:"index"`
+	IsDegraded    bool      `json:"is_degraded,omitempty"`
+	StatusCode    int       `json:"-"`
+	ResponseTime  int64     `json:"response_time"` // in milliseconds
+	InMaintenance bool      `json:"in_maintenance,omitempty"`
+	Suppressed    bool      `json:"suppressed,omitempty"`
+	CreatedAt     time.Time `json:"created_at" gorm:"index"`
+	Monitor       Monitor   `json:"-" gorm:"foreignKey:MonitorID"`
 }
 
 type Incident struct {
-	ID          string        `json:"id" gorm:"primaryKey"`
-	MonitorID   string        `json:"monitor_id"`
-	IncidentID  uint64        `json:"-"`
-	Type        incident.Type `json:"type" gorm:"index"`
-	Description string        `json:"description"`
-	CreatedAt   time.Time     `json:"created_at"`
-	SolvedAt    *time.Time    `json:"solved_at" gorm:"index"`
-	Monitor     Monitor       `gorm:"foreignKey:MonitorID"`
+	ID               string          `json:"id" gorm:"primaryKey"`
+	MonitorID        string          `json:"monitor_id" gorm:"index"`
+	IncidentID       uint64          `json:"incident_id,omitempty"` // ID on the master, 0 if not reported
+	Type             incident.Type   `json:"type" gorm:"index"`
+	Description      string          `json:"description"`
+	CreatedAt        time.Time       `json:"created_at" gorm:"index"`
+	SolvedAt         *time.Time      `json:"solved_at" gorm:"index"`
+	AffectedMonitors []string        `json:"affected_monitors,omitempty" gorm:"serializer:json"` // IDs of the dependent monitors suppressed
+	Status           incident.Status `json:"status,omitempty"`
+	AcknowledgedAt   *time.Time      `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy   string          `json:"acknowledged_by,omitempty"`
+	Duration         *int64          `json:"duration,omitempty" gorm:"-"` // in seconds
+	Monitor          Monitor         `gorm:"foreignKey:MonitorID"`
+	Events           []IncidentEvent `json:"events,omitempty" gorm:"foreignKey:IncidentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 }
 
+// IncidentEvent is an entry on the timeline of an incident.
+type IncidentEvent struct {
+	ID         string    `json:"id" gorm:"primaryKey"`
+	IncidentID string    `json:"-" gorm:"index"`
+	Type       string    `json:"type"`
+	Message    string    `json:"message"`
+	Actor      string    `json:"actor"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+const (
+	IncidentEventCreated       = "created"
+	IncidentEventUpdated       = "updated"
+	IncidentEventResolved      = "resolved"
+	IncidentEventNotified      = "notified"
+	IncidentEventNotifyFailed  = "notify_failed"
+	IncidentEventStatusSynced  = "status_synced"
+	IncidentEventStatusFailed  = "status_sync_failed"
+	IncidentEventAffected      = "affected_monitor"
+	IncidentEventAcknowledged  = "acknowledged"
+	IncidentEventNote          = "note"
+	IncidentEventFalsePositive = "false_positive"
+)
+
+// Actor of incident events raised by the monitor itself
+const SystemActor = "system"
+
 type Response struct {
 	Message string `json:"message"`
 	Data    any    `json:"data,omitempty"`
@@ -63,6 +110,14 @@ func (h *MonitorHistory) BeforeCreate(tx *gorm.DB) (err error) {
 	return nil
 }
 
+func (e *IncidentEvent) BeforeCreate(tx *gorm.DB) (err error) {
+	if e.ID == "" {
+		e.ID = helper.GenerateRandomID()
+	}
+
+	return nil
+}
+
 func (m Monitor) IsExists() bool {
 	return !m.CreatedAt.IsZero()
 }
@@ -71,6 +126,22 @@ func (m Monitor) IsNotExists() bool {
 	return m.CreatedAt.IsZero()
 }
 
+// Matches reports whether ref, a monitor ID or URL, refers to the monitor. A
+// URL refers to every monitor checking it.
+func (m Monitor) Matches(ref string) bool {
+	return ref == m.ID || helper.NormalizeURL(ref) == m.URL
+}
+
+// DisplayName returns the configured name of the monitor, falling back to
+// its URL.
+func (m Monitor) DisplayName() string {
+	if m.Name != "" {
+		return m.Name
+	}
+
+	return m.URL
+}
+
 func (i Incident) IsExists() bool {
 	return !i.CreatedAt.IsZero()
 }
@@ -83,6 +154,20 @@ func (i Incident) IsSolved() bool {
 	return i.SolvedAt != nil
 }
 
+func (i Incident) IsAcknowledged() bool {
+	return i.AcknowledgedAt != nil
+}
+
+// GetDuration returns how long the incident lasted, or has lasted so far
+// if it is still open.
+func (i Incident) GetDuration(now time.Time) time.Duration {
+	if i.SolvedAt != nil {
+		return i.SolvedAt.Sub(i.CreatedAt)
+	}
+
+	return now.Sub(i.CreatedAt)
+}
+
 func (r Response) Print() {
 	data, err := json.Marshal(r)
 
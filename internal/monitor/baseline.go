@@ -0,0 +1,175 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"uptime-go/internal/helper"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+	"uptime-go/internal/net"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// Histories used to learn the response time baseline of a monitor
+	baselinePeriod = 14 * 24 * time.Hour
+
+	// Minimum successful checks in the same hour of day before the
+	// baseline is trusted
+	baselineMinSamples = 20
+
+	// Modified z-score above which a response time is anomalous
+	anomalyThreshold = 3.5
+
+	// Consecutive anomalous checks required to open an incident
+	anomalySustainedChecks = 5
+
+	// Lower bound of the MAD relative to the median, so a very stable
+	// baseline does not turn every small jitter into an anomaly
+	madFloorRatio = 0.05
+)
+
+// baseline is the typical response time of a monitor for one hour of day.
+type baseline struct {
+	median    float64 // in milliseconds
+	mad       float64 // median absolute deviation, in milliseconds
+	samples   int
+	expiresAt time.Time
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+
+	return sorted[middle]
+}
+
+func medianAbsoluteDeviation(values []float64, center float64) float64 {
+	deviations := make([]float64, len(values))
+	for i, value := range values {
+		deviations[i] = math.Abs(value - center)
+	}
+
+	return median(deviations)
+}
+
+// score returns the modified z-score of a response time, positive when the
+// response is slower than usual.
+func (b *baseline) score(responseTime float64) float64 {
+	mad := math.Max(b.mad, b.median*madFloorRatio)
+	if mad == 0 {
+		return 0
+	}
+
+	return 0.6745 * (responseTime - b.median) / mad
+}
+
+// getBaseline returns the baseline for the hour of day of t, learned from
+// successful checks outside maintenance. Baselines are cached for an hour.
+func (m *UptimeMonitor) getBaseline(monitor *models.Monitor, t time.Time) (*baseline, error) {
+	key := fmt.Sprintf("%s/%d", monitor.ID, t.Hour())
+	if cached, ok := m.baselines.Load(key); ok && time.Now().Before(cached.(*baseline).expiresAt) {
+		return cached.(*baseline), nil
+	}
+
+	histories, err := m.db.GetHistoriesSince(monitor.ID, t.Add(-baselinePeriod))
+	if err != nil {
+		return nil, err
+	}
+
+	var values []float64
+	for _, history := range histories {
+		if !history.IsUp || history.InMaintenance || history.CreatedAt.Local().Hour() != t.Hour() {
+			continue
+		}
+		values = append(values, float64(history.ResponseTime))
+	}
+
+	center := median(values)
+	b := &baseline{
+		median:    center,
+		mad:       medianAbsoluteDeviation(values, center),
+		samples:   len(values),
+		expiresAt: time.Now().Add(time.Hour),
+	}
+	m.baselines.Store(key, b)
+
+	return b, nil
+}
+
+// handleBaseline opens a performance regression incident when the response
+// time stays well above the learned baseline for several checks, and
+// resolves it once a check is back within the baseline.
+func (m *UptimeMonitor) handleBaseline(monitor *models.Monitor, result *net.CheckResults) bool {
+	now := time.Now()
+	b, err := m.getBaseline(monitor, now)
+	if err != nil {
+		log.Error().Err(err).Msgf("%s - failed to compute response time baseline", monitor.URL)
+		return false
+	}
+
+	if b.samples < baselineMinSamples {
+		return false
+	}
+
+	responseTime := float64(result.ResponseTime.Milliseconds())
+	if b.score(responseTime) <= anomalyThreshold {
+		return m.resolveIncidents(monitor, incident.PerformanceRegression)
+	}
+
+	lastIncident := m.db.GetLastIncident(monitor.ID, incident.PerformanceRegression)
+	if lastIncident.IsExists() {
+		return false // Incident already recorded
+	}
+
+	histories, err := m.db.GetRecentHistories(monitor.ID, anomalySustainedChecks-1)
+	if err != nil {
+		log.Error().Err(err).Msgf("%s - failed to get recent histories", monitor.URL)
+		return false
+	}
+
+	if len(histories) < anomalySustainedChecks-1 {
+		return false
+	}
+
+	for _, history := range histories {
+		if !history.IsUp || b.score(float64(history.ResponseTime)) <= anomalyThreshold {
+			return false // Not sustained
+		}
+	}
+
+	inc := &models.Incident{
+		ID:          helper.GenerateRandomID(),
+		MonitorID:   monitor.ID,
+		Type:        incident.PerformanceRegression,
+		Description: fmt.Sprintf("Response time %.0fms is significantly above the baseline of %.0fms for the last %d checks", responseTime, b.median, anomalySustainedChecks),
+		Monitor:     *monitor,
+	}
+
+	attributes := map[string]any{
+		"response_time":     responseTime / 1000,
+		"baseline_median":   b.median / 1000,
+		"baseline_mad":      b.mad / 1000,
+		"baseline_samples":  b.samples,
+		"sustained_checks":  anomalySustainedChecks,
+		"anomaly_threshold": anomalyThreshold,
+	}
+
+	m.openIncident(inc, incident.LOW, incident.EventWebsitePerformanceRegression, attributes)
+	log.Warn().Msgf("%s - New Incident detected! - Type: %s", monitor.URL, inc.Type)
+
+	return true
+}
@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+	"uptime-go/internal/net/database"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMedian(t *testing.T) {
+	assert.Equal(t, 0.0, median(nil))
+	assert.Equal(t, 2.0, median([]float64{3, 1, 2}))
+	assert.Equal(t, 2.5, median([]float64{4, 1, 3, 2}))
+	assert.Equal(t, 1.0, medianAbsoluteDeviation([]float64{1, 2, 3, 4, 100}, 3))
+}
+
+func TestBaselineScore(t *testing.T) {
+	b := &baseline{median: 100, mad: 10}
+	assert.InDelta(t, 6.745, b.score(200), 0.001)
+	assert.Less(t, b.score(50), 0.0)
+
+	// A zero MAD falls back to a share of the median
+	stable := &baseline{median: 100, mad: 0}
+	assert.InDelta(t, 1.349, stable.score(110), 0.001)
+}
+
+func TestCheckWebsitePerformanceRegression(t *testing.T) {
+	// The fixtures are at the same hour on previous days, which a DST
+	// transition would shift
+	local := time.Local
+	time.Local = time.UTC
+	t.Cleanup(func() { time.Local = local })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	db, _ := database.InitializeTestDatabase()
+	uptimeMonitor, _ := NewUptimeMonitor(db, nil)
+	monitor := &models.Monitor{
+		ID:                    "regression",
+		URL:                   server.URL,
+		Interval:              1 * time.Minute,
+		ResponseTimeThreshold: 5 * time.Second,
+		AnomalyDetection:      true,
+	}
+	db.DB.Create(monitor)
+
+	// Same hour of day on previous days, around 10ms
+	for i := range baselineMinSamples {
+		db.DB.Create(&models.MonitorHistory{
+			MonitorID:    monitor.ID,
+			IsUp:         true,
+			ResponseTime: int64(9 + i%3),
+			CreatedAt:    time.Now().AddDate(0, 0, -(i%7 + 1)),
+		})
+	}
+
+	// Not sustained yet
+	uptimeMonitor.checkWebsite(monitor)
+	assert.True(t, uptimeMonitor.db.GetLastIncident(monitor.ID, incident.PerformanceRegression).IsNotExists())
+
+	for range anomalySustainedChecks - 1 {
+		uptimeMonitor.checkWebsite(monitor)
+	}
+	assert.True(t, uptimeMonitor.db.GetLastIncident(monitor.ID, incident.PerformanceRegression).IsExists())
+}
+
+func TestCheckWebsiteDownSolvesPerformanceRegression(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	db, _ := database.InitializeTestDatabase()
+	uptimeMonitor, _ := NewUptimeMonitor(db, nil)
+	monitor := &models.Monitor{
+		ID:                    "down",
+		URL:                   server.URL,
+		Interval:              1 * time.Minute,
+		ResponseTimeThreshold: 5 * time.Second,
+		AnomalyDetection:      true,
+		Incidents:             []models.Incident{{ID: "slow", Type: incident.PerformanceRegression}},
+	}
+	db.DB.Create(monitor)
+
+	uptimeMonitor.checkWebsite(monitor)
+
+	assert.True(t, uptimeMonitor.db.GetLastIncident(monitor.ID, incident.UnexpectedStatusCode).IsExists())
+	assert.True(t, uptimeMonitor.db.GetLastIncident(monitor.ID, incident.PerformanceRegression).IsNotExists())
+}
@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"uptime-go/internal/helper"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+	"uptime-go/internal/net"
+
+	"github.com/rs/zerolog/log"
+)
+
+// responseTimeAverage returns the response time compared against the
+// degraded threshold: the current check alone, or the rolling average over
+// the last DegradedAverage successful checks.
+func (m *UptimeMonitor) responseTimeAverage(monitor *models.Monitor, result *net.CheckResults) time.Duration {
+	if monitor.DegradedAverage <= 1 {
+		return result.ResponseTime
+	}
+
+	histories, err := m.db.GetRecentSuccessfulHistories(monitor.ID, monitor.DegradedAverage-1)
+	if err != nil {
+		log.Error().Err(err).Msgf("%s - failed to get recent histories", monitor.URL)
+		return result.ResponseTime
+	}
+
+	total := result.ResponseTime
+	count := 1
+	for _, history := range histories {
+		total += time.Duration(history.ResponseTime) * time.Millisecond
+		count++
+	}
+
+	return total / time.Duration(count)
+}
+
+// isDegraded reports whether a successful check is slower than the monitor's
+// degraded threshold.
+func (m *UptimeMonitor) isDegraded(monitor *models.Monitor, result *net.CheckResults) bool {
+	if monitor.DegradedThreshold <= 0 || !result.IsUp {
+		return false
+	}
+
+	return m.responseTimeAverage(monitor, result) >= monitor.DegradedThreshold
+}
+
+func (m *UptimeMonitor) handleDegraded(monitor *models.Monitor, result *net.CheckResults) bool {
+	// return true if new incident created; else false
+
+	lastIncident := m.db.GetLastIncident(monitor.ID, incident.Degraded)
+	if lastIncident.IsExists() {
+		return false // Incident already recorded
+	}
+
+	responseTime := m.responseTimeAverage(monitor, result)
+	inc := &models.Incident{
+		ID:          helper.GenerateRandomID(),
+		MonitorID:   monitor.ID,
+		Type:        incident.Degraded,
+		Description: fmt.Sprintf("Response time %s exceeds degraded threshold %s", responseTime.Round(time.Millisecond), monitor.DegradedThreshold),
+		Monitor:     *monitor,
+	}
+
+	attributes := map[string]any{
+		"response_time":      responseTime.Seconds(),
+		"degraded_threshold": monitor.DegradedThreshold.Seconds(),
+		"average_checks":     monitor.DegradedAverage,
+	}
+
+	m.openIncident(inc, incident.MEDIUM, incident.EventWebsiteDegraded, attributes)
+	log.Warn().Msgf("%s - New Incident detected! - Type: %s", monitor.URL, inc.Type)
+
+	return true
+}
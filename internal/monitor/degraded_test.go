@@ -0,0 +1,141 @@
+package monitor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+	"uptime-go/internal/net"
+	"uptime-go/internal/net/database"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDegraded(t *testing.T) {
+	testCases := []struct {
+		name      string
+		monitor   models.Monitor
+		result    net.CheckResults
+		histories []int64
+		down      int
+		expected  bool
+	}{
+		{
+			name:     "threshold disabled",
+			monitor:  models.Monitor{},
+			result:   net.CheckResults{IsUp: true, ResponseTime: time.Minute},
+			expected: false,
+		},
+		{
+			name:     "slow check",
+			monitor:  models.Monitor{DegradedThreshold: time.Second},
+			result:   net.CheckResults{IsUp: true, ResponseTime: 2 * time.Second},
+			expected: true,
+		},
+		{
+			name:     "down check is not degraded",
+			monitor:  models.Monitor{DegradedThreshold: time.Second},
+			result:   net.CheckResults{IsUp: false, ResponseTime: 2 * time.Second},
+			expected: false,
+		},
+		{
+			name:      "single slow check within fast average",
+			monitor:   models.Monitor{ID: "avg", DegradedThreshold: time.Second, DegradedAverage: 4},
+			result:    net.CheckResults{IsUp: true, ResponseTime: 2 * time.Second},
+			histories: []int64{200, 300, 100},
+			expected:  false,
+		},
+		{
+			name:      "slow average",
+			monitor:   models.Monitor{ID: "avg", DegradedThreshold: time.Second, DegradedAverage: 3},
+			result:    net.CheckResults{IsUp: true, ResponseTime: 900 * time.Millisecond},
+			histories: []int64{1500, 1200},
+			expected:  true,
+		},
+		{
+			name:      "down checks are not averaged",
+			monitor:   models.Monitor{ID: "avg", DegradedThreshold: time.Second, DegradedAverage: 3},
+			result:    net.CheckResults{IsUp: true, ResponseTime: 900 * time.Millisecond},
+			histories: []int64{1500, 1200},
+			down:      2,
+			expected:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, _ := database.InitializeTestDatabase()
+			uptimeMonitor, _ := NewUptimeMonitor(db, nil)
+
+			for _, responseTime := range tc.histories {
+				db.DB.Create(&models.MonitorHistory{MonitorID: tc.monitor.ID, IsUp: true, ResponseTime: responseTime})
+			}
+			for range tc.down {
+				db.DB.Create(&models.MonitorHistory{MonitorID: tc.monitor.ID, IsUp: false})
+			}
+
+			assert.Equal(t, tc.expected, uptimeMonitor.isDegraded(&tc.monitor, &tc.result))
+		})
+	}
+}
+
+func TestCheckWebsiteDegraded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	db, _ := database.InitializeTestDatabase()
+	uptimeMonitor, _ := NewUptimeMonitor(db, nil)
+	monitor := &models.Monitor{
+		URL:                   server.URL,
+		Interval:              1 * time.Minute,
+		ResponseTimeThreshold: 5 * time.Second,
+		DegradedThreshold:     10 * time.Millisecond,
+	}
+	db.DB.Create(monitor)
+
+	uptimeMonitor.checkWebsite(monitor)
+
+	db.DB.First(monitor)
+	assert.True(t, *monitor.IsUp)
+	assert.True(t, *monitor.IsDegraded)
+	assert.True(t, uptimeMonitor.db.GetLastIncident(monitor.ID, incident.Degraded).IsExists())
+
+	monitor.DegradedThreshold = 5 * time.Second
+	uptimeMonitor.checkWebsite(monitor)
+
+	assert.False(t, *monitor.IsDegraded)
+	assert.True(t, uptimeMonitor.db.GetLastIncident(monitor.ID, incident.Degraded).IsNotExists())
+}
+
+func TestCheckWebsiteDownSolvesDegraded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	db, _ := database.InitializeTestDatabase()
+	uptimeMonitor, _ := NewUptimeMonitor(db, nil)
+	monitor := &models.Monitor{
+		ID:                    "down",
+		URL:                   server.URL,
+		Interval:              1 * time.Minute,
+		ResponseTimeThreshold: 5 * time.Second,
+		DegradedThreshold:     time.Second,
+		Incidents:             []models.Incident{{ID: "slow", Type: incident.Degraded}},
+	}
+	db.DB.Create(monitor)
+
+	uptimeMonitor.checkWebsite(monitor)
+
+	assert.True(t, uptimeMonitor.db.GetLastIncident(monitor.ID, incident.UnexpectedStatusCode).IsExists())
+	assert.True(t, uptimeMonitor.db.GetLastIncident(monitor.ID, incident.Degraded).IsNotExists())
+
+	slow, err := db.GetIncident("slow")
+	assert.NoError(t, err)
+	assert.Equal(t, incident.Resolved, slow.Status)
+}
@@ -0,0 +1,96 @@
+package monitor
+
+import (
+	"fmt"
+
+	"uptime-go/internal/helper"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+	"uptime-go/internal/net"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// Number of checks, including the current one, used to detect flapping
+	flapHistorySize = 20
+
+	// A monitor starts flapping when at least this share of consecutive
+	// checks changed state, and stops once it drops to flapStopRatio.
+	// The gap between both avoids toggling the flapping state itself.
+	flapStartRatio = 0.5
+	flapStopRatio  = 0.25
+)
+
+// stateChangeRatio returns the share of consecutive states that differ.
+func stateChangeRatio(states []bool) float64 {
+	if len(states) < 2 {
+		return 0
+	}
+
+	changes := 0
+	for i := 1; i < len(states); i++ {
+		if states[i] != states[i-1] {
+			changes++
+		}
+	}
+
+	return float64(changes) / float64(len(states)-1)
+}
+
+// handleFlapping opens or resolves the flapping incident of a monitor based on
+// its recent state changes and reports whether the monitor is flapping.
+// While flapping, individual up/down transitions should not be handled.
+func (m *UptimeMonitor) handleFlapping(monitor *models.Monitor, result *net.CheckResults) bool {
+	histories, err := m.db.GetRecentHistories(monitor.ID, flapHistorySize-1)
+	if err != nil {
+		log.Error().Err(err).Msgf("%s - failed to get recent histories", monitor.URL)
+		return false
+	}
+
+	states := []bool{result.IsUp}
+	for _, history := range histories {
+		states = append(states, history.IsUp)
+	}
+
+	ratio := stateChangeRatio(states)
+	lastIncident := m.db.GetLastIncident(monitor.ID, incident.Flapping)
+
+	if lastIncident.IsExists() {
+		if ratio > flapStopRatio {
+			return true
+		}
+
+		log.Info().Msgf("%s - Monitor is stable again - State change ratio: %.2f", monitor.URL, ratio)
+		m.resolveIncidents(monitor, incident.Flapping)
+		return false
+	}
+
+	// Not enough checks yet to tell flapping from a single outage
+	if len(states) < flapHistorySize/2 || ratio < flapStartRatio {
+		return false
+	}
+
+	// Replace individual down incidents with a single flapping incident
+	for _, incidentType := range incident.WebsiteDownTypes {
+		m.resolveIncidents(monitor, incidentType)
+	}
+
+	inc := &models.Incident{
+		ID:          helper.GenerateRandomID(),
+		MonitorID:   monitor.ID,
+		Type:        incident.Flapping,
+		Description: fmt.Sprintf("Monitor is flapping: state changed in %.0f%% of the last %d checks", ratio*100, len(states)),
+		Monitor:     *monitor,
+	}
+
+	attributes := map[string]any{
+		"state_change_ratio": ratio,
+		"checks":             len(states),
+	}
+
+	m.openIncident(inc, incident.MEDIUM, incident.EventWebsiteFlapping, attributes)
+	log.Warn().Msgf("%s - Monitor is flapping - State change ratio: %.2f", monitor.URL, ratio)
+
+	return true
+}
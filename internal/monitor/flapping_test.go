@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+	"uptime-go/internal/net/database"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateChangeRatio(t *testing.T) {
+	assert.Equal(t, 0.0, stateChangeRatio(nil))
+	assert.Equal(t, 0.0, stateChangeRatio([]bool{true, true, true}))
+	assert.Equal(t, 1.0, stateChangeRatio([]bool{true, false, true}))
+	assert.Equal(t, 0.5, stateChangeRatio([]bool{true, false, false}))
+}
+
+func TestCheckWebsiteFlapping(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	db, _ := database.InitializeTestDatabase()
+	uptimeMonitor, _ := NewUptimeMonitor(db, nil)
+	monitor := &models.Monitor{
+		ID:                    "flappy",
+		URL:                   server.URL,
+		Interval:              1 * time.Minute,
+		ResponseTimeThreshold: 5 * time.Second,
+		Incidents:             []models.Incident{{ID: "down", Type: incident.UnexpectedStatusCode}},
+	}
+	db.DB.Create(monitor)
+
+	// Alternate up and down, newest history is up
+	start := time.Now().Add(-time.Hour)
+	for i := range flapHistorySize - 1 {
+		db.DB.Create(&models.MonitorHistory{
+			MonitorID: monitor.ID,
+			IsUp:      i%2 == 0,
+			CreatedAt: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	uptimeMonitor.checkWebsite(monitor)
+
+	assert.True(t, uptimeMonitor.db.GetLastIncident(monitor.ID, incident.Flapping).IsExists())
+	assert.True(t, uptimeMonitor.db.GetLastIncident(monitor.ID, incident.UnexpectedStatusCode).IsNotExists())
+
+	// A stable run of failed checks clears flapping and opens a regular incident
+	for i := range flapHistorySize {
+		db.DB.Create(&models.MonitorHistory{
+			MonitorID: monitor.ID,
+			IsUp:      false,
+			CreatedAt: time.Now().Add(time.Duration(i) * time.Second),
+		})
+	}
+
+	uptimeMonitor.checkWebsite(monitor)
+
+	assert.True(t, uptimeMonitor.db.GetLastIncident(monitor.ID, incident.Flapping).IsNotExists())
+	assert.True(t, uptimeMonitor.db.GetLastIncident(monitor.ID, incident.UnexpectedStatusCode).IsExists())
+}
@@ -0,0 +1,106 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+
+	"uptime-go/internal/events"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+	"uptime-go/internal/net"
+	"uptime-go/internal/net/database"
+
+	"github.com/rs/zerolog/log"
+)
+
+// openIncident notifies the master and the local notifiers about a new
+// incident, stores it and starts its timeline.
+func (m *UptimeMonitor) openIncident(inc *models.Incident, severity incident.Severity, event string, attributes map[string]any) {
+	result := notifyIncident(inc, severity, event, attributes)
+
+	m.db.Upsert(inc)
+	m.recordEvent(inc, models.IncidentEventCreated, inc.Description)
+	m.recordNotification(inc, result)
+}
+
+// notification is the outcome of reporting an incident to the master and to
+// the local notifiers.
+type notification struct {
+	master error
+	local  error
+}
+
+// notifyIncident reports an incident, setting its master ID when the master
+// received it.
+func notifyIncident(inc *models.Incident, severity incident.Severity, event string, attributes map[string]any) notification {
+	id, err := net.NotifyIncident(inc, severity, event, attributes)
+	if err == nil {
+		inc.IncidentID = id
+	}
+
+	return notification{master: err, local: net.NotifyNotifiers(inc, severity, event, attributes)}
+}
+
+func (m *UptimeMonitor) recordEvent(inc *models.Incident, eventType string, message string) {
+	if err := m.db.AddIncidentEvent(inc.ID, eventType, message, models.SystemActor); err != nil {
+		log.Error().Err(err).Msg("failed to record incident event")
+	}
+
+	// Subscribers read the incident from another goroutine
+	snapshot := *inc
+	snapshot.Monitor.Histories = nil
+	m.events.Publish(events.IncidentEventType(eventType), events.IncidentChange{
+		Incident: &snapshot,
+		Event:    eventType,
+		Message:  message,
+		Actor:    models.SystemActor,
+	})
+}
+
+func (m *UptimeMonitor) recordNotification(inc *models.Incident, result notification) {
+	switch {
+	case errors.Is(result.master, net.ErrStandalone):
+	case result.master != nil:
+		m.recordEvent(inc, models.IncidentEventNotifyFailed, result.master.Error())
+	default:
+		m.recordEvent(inc, models.IncidentEventNotified, fmt.Sprintf("Incident reported to master with ID %d", inc.IncidentID))
+	}
+
+	switch {
+	case result.local != nil:
+		m.recordEvent(inc, models.IncidentEventNotifyFailed, fmt.Sprintf("Failed to notify local notifiers: %v", result.local))
+	case net.HasNotifiers():
+		m.recordEvent(inc, models.IncidentEventNotified, "Incident sent to local notifiers")
+	}
+}
+
+// SyncIncidentStatus sends the status of an incident to the master, unless
+// running standalone, and to the local notifiers, and records the outcome
+// on the incident timeline.
+func SyncIncidentStatus(db database.Store, inc *models.Incident, status incident.Status, actor string) error {
+	record := func(eventType string, message string) {
+		if err := db.AddIncidentEvent(inc.ID, eventType, message, actor); err != nil {
+			log.Error().Err(err).Msg("failed to record incident event")
+		}
+	}
+
+	err := net.UpdateIncidentStatus(inc, status)
+	switch {
+	case errors.Is(err, net.ErrStandalone):
+		err = nil
+	case err != nil:
+		record(models.IncidentEventStatusFailed, fmt.Sprintf("Failed to send status '%s' to master: %v", status, err))
+	default:
+		record(models.IncidentEventStatusSynced, fmt.Sprintf("Status '%s' sent to master", status))
+	}
+
+	localErr := net.UpdateNotifiersStatus(inc, status)
+	switch {
+	case localErr != nil:
+		record(models.IncidentEventStatusFailed, fmt.Sprintf("Failed to send status '%s' to local notifiers: %v", status, localErr))
+	case net.HasNotifiers():
+		record(models.IncidentEventStatusSynced, fmt.Sprintf("Status '%s' sent to local notifiers", status))
+	}
+
+	return errors.Join(err, localErr)
+}
@@ -1,12 +1,14 @@
 package monitor
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
+	"slices"
 	"sync"
 	"time"
 
+	"uptime-go/internal/events"
 	"uptime-go/internal/helper"
 	"uptime-go/internal/incident"
 	"uptime-go/internal/models"
@@ -18,20 +20,31 @@ import (
 
 // UptimeMonitor represents a service that periodically checks website uptime
 type UptimeMonitor struct {
-	configs  []*models.Monitor
-	db       *database.Database
-	stopChan chan struct{}
-	wg       sync.WaitGroup
+	configs   []*models.Monitor
+	db        database.Store
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+	baselines sync.Map // response time baselines by monitor ID and hour
+	events    *events.Bus
+	locks     sync.Map // check locks by monitor ID
 }
 
-func NewUptimeMonitor(db *database.Database, configs []*models.Monitor) (*UptimeMonitor, error) {
+var ErrMonitorNotFound = errors.New("monitor not found")
+
+func NewUptimeMonitor(db database.Store, configs []*models.Monitor) (*UptimeMonitor, error) {
 	return &UptimeMonitor{
 		configs:  configs,
 		db:       db,
 		stopChan: make(chan struct{}),
+		events:   events.NewBus(),
 	}, nil
 }
 
+// Events returns the bus receiving check results and incident changes.
+func (m *UptimeMonitor) Events() *events.Bus {
+	return m.events
+}
+
 func (m *UptimeMonitor) Start() {
 	log.Info().Msgf("Starting uptime monitoring for %d websites", len(m.configs))
 
@@ -74,31 +87,126 @@ func (m *UptimeMonitor) monitorWebsite(cfg *models.Monitor) {
 	}
 }
 
-func (m *UptimeMonitor) checkWebsite(monitor *models.Monitor) {
-	nc := &net.NetworkConfig{
+// CheckNow immediately checks the monitor with the given ID or name, recording
+// the result and incident transitions like a scheduled check.
+func (m *UptimeMonitor) CheckNow(idOrName string) (*events.Check, error) {
+	for _, cfg := range m.configs {
+		if cfg.ID == idOrName || (cfg.Name != "" && cfg.Name == idOrName) {
+			check := m.checkWebsite(cfg)
+			return &check, nil
+		}
+	}
+
+	return nil, ErrMonitorNotFound
+}
+
+// CheckOnce checks a monitor without recording the result, e.g. for ad-hoc
+// checks from the command line.
+func CheckOnce(monitor *models.Monitor) events.Check {
+	result, err := newNetworkConfig(monitor).CheckWebsite()
+	if err != nil && result.FailureType == "" {
+		result.FailureType = net.ClassifyError(err)
+	}
+
+	degraded := result.IsUp && monitor.DegradedThreshold > 0 && result.ResponseTime >= monitor.DegradedThreshold
+
+	statusText := "DOWN"
+	if degraded {
+		statusText = "DEGRADED"
+	} else if result.IsUp {
+		statusText = "UP"
+	}
+
+	return newCheck(monitor, result, statusText, degraded)
+}
+
+func newNetworkConfig(monitor *models.Monitor) *net.NetworkConfig {
+	return &net.NetworkConfig{
 		URL:             monitor.URL,
 		RefreshInterval: monitor.Interval,
 		Timeout:         monitor.ResponseTimeThreshold,
 		SkipSSL:         !monitor.CertificateMonitoring,
 	}
+}
+
+func newCheck(monitor *models.Monitor, result *net.CheckResults, statusText string, degraded bool) events.Check {
+	return events.Check{
+		MonitorID:              monitor.ID,
+		URL:                    monitor.URL,
+		Name:                   monitor.Name,
+		Status:                 statusText,
+		IsUp:                   result.IsUp,
+		IsDegraded:             degraded,
+		StatusCode:             result.StatusCode,
+		ResponseTime:           result.ResponseTime.Milliseconds(),
+		FailureType:            result.FailureType,
+		Error:                  result.ErrorMessage,
+		CertificateExpiredDate: result.SSLExpiredDate,
+		CheckedAt:              result.LastCheck,
+	}
+}
+
+// lock serializes checks of a monitor, as on-demand checks may run while a
+// scheduled check is in progress.
+func (m *UptimeMonitor) lock(monitor *models.Monitor) func() {
+	value, _ := m.locks.LoadOrStore(monitor.ID, &sync.Mutex{})
+	mutex := value.(*sync.Mutex)
+	mutex.Lock()
+
+	return mutex.Unlock
+}
+
+func (m *UptimeMonitor) checkWebsite(monitor *models.Monitor) events.Check {
+	defer m.lock(monitor)()
 
-	result, err := nc.CheckWebsite()
+	result, err := newNetworkConfig(monitor).CheckWebsite()
 	if err != nil {
 		log.Error().Err(err).Msgf("Error checking %s", monitor.URL)
 	}
 
 	statusText := "UP"
 	now := time.Now()
+	maintenance := m.db.GetActiveMaintenance(*monitor, now)
+	flapping := maintenance == nil && m.handleFlapping(monitor, result)
+	suppressed := false
+	degraded := m.isDegraded(monitor, result)
 	if result.IsUp {
 		if monitor.LastUp == nil {
 			monitor.LastUp = &now
 		}
 
-		m.resolveIncidents(monitor, incident.UnexpectedStatusCode)
-		m.resolveIncidents(monitor, incident.Timeout)
-		if monitor.CertificateMonitoring {
+		if !flapping {
+			for _, incidentType := range incident.WebsiteDownTypes {
+				m.resolveIncidents(monitor, incidentType)
+			}
+		}
+
+		if degraded {
+			statusText = "DEGRADED"
+			if maintenance == nil {
+				m.handleDegraded(monitor, result)
+			}
+		} else {
+			m.resolveIncidents(monitor, incident.Degraded)
+		}
+
+		if monitor.AnomalyDetection && maintenance == nil {
+			m.handleBaseline(monitor, result)
+		}
+
+		if monitor.CertificateMonitoring && maintenance == nil {
 			m.handleSSL(monitor, result)
 		}
+	} else if flapping {
+		statusText = "DOWN (flapping)"
+	} else if maintenance != nil {
+		statusText = "DOWN (maintenance)"
+		log.Info().Msgf("%s - Incident suppressed by maintenance window %q", monitor.URL, maintenance.Name)
+	} else if parent := m.getDownDependency(monitor); parent != nil {
+		statusText = "DOWN (dependency)"
+		suppressed = true
+		m.addAffectedMonitor(parent, monitor)
+		log.Info().Msgf("%s - Incident suppressed because dependency %s is down", monitor.URL, parent.URL)
 	} else {
 		statusText = "DOWN"
 		m.handleWebsiteDown(monitor, result, err)
@@ -107,14 +215,18 @@ func (m *UptimeMonitor) checkWebsite(monitor *models.Monitor) {
 	responseTime := result.ResponseTime.Milliseconds()
 	monitor.UpdatedAt = result.LastCheck
 	monitor.IsUp = &result.IsUp
+	monitor.IsDegraded = &degraded
 	monitor.StatusCode = &result.StatusCode
 	monitor.ResponseTime = &responseTime
 	monitor.CertificateExpiredDate = result.SSLExpiredDate
 	monitor.Histories = []models.MonitorHistory{
 		{
-			IsUp:         result.IsUp,
-			StatusCode:   result.StatusCode,
-			ResponseTime: responseTime,
+			IsUp:          result.IsUp,
+			IsDegraded:    degraded,
+			StatusCode:    result.StatusCode,
+			ResponseTime:  responseTime,
+			InMaintenance: maintenance != nil,
+			Suppressed:    suppressed,
 		},
 	}
 
@@ -124,13 +236,26 @@ func (m *UptimeMonitor) checkWebsite(monitor *models.Monitor) {
 	if err := m.db.Upsert(monitor); err != nil {
 		log.Error().Err(err).Msg("Failed to save result to database")
 	}
+
+	check := newCheck(monitor, result, statusText, degraded)
+	check.InMaintenance = maintenance != nil
+	check.Suppressed = suppressed
+	m.events.Publish(events.CheckCompleted, check)
+
+	return check
 }
 
 func (m *UptimeMonitor) handleWebsiteDown(monitor *models.Monitor, result *net.CheckResults, err error) (bool, incident.Type) {
 	// return true if new incident created; else false, incident type
 
 	var description string
-	incidentType := incident.UnexpectedStatusCode
+	incidentType := result.FailureType
+	if incidentType == "" {
+		incidentType = net.ClassifyError(err)
+	}
+	if incidentType == "" {
+		incidentType = incident.UnexpectedStatusCode
+	}
 
 	attributes := map[string]any{
 		"status_code":   result.StatusCode,
@@ -138,23 +263,37 @@ func (m *UptimeMonitor) handleWebsiteDown(monitor *models.Monitor, result *net.C
 		"error_message": result.ErrorMessage,
 	}
 
-	if err != nil {
-		if os.IsTimeout(err) {
-			incidentType = incident.Timeout
-			result.ResponseTime = monitor.ResponseTimeThreshold
-			description = fmt.Sprintf("Request timed out: %s", monitor.URL)
-		} else {
-			description = fmt.Sprintf("An unexpected error occurred at %s", monitor.URL)
-		}
-	} else {
+	switch {
+	case incidentType == incident.Timeout:
+		result.ResponseTime = monitor.ResponseTimeThreshold
+		description = fmt.Sprintf("Request timed out: %s", monitor.URL)
+	case incidentType == incident.DNSFailure:
+		description = fmt.Sprintf("DNS lookup failed: %s", monitor.URL)
+	case incidentType == incident.ConnectionRefused:
+		description = fmt.Sprintf("Connection refused: %s", monitor.URL)
+	case incidentType == incident.ConnectionReset:
+		description = fmt.Sprintf("Connection reset or closed prematurely: %s", monitor.URL)
+	case incidentType == incident.TLSHandshakeFailure:
+		description = fmt.Sprintf("TLS handshake failed: %s", monitor.URL)
+	case incidentType == incident.CertificateInvalid:
+		description = fmt.Sprintf("Invalid TLS certificate: %s", monitor.URL)
+	case err != nil:
+		description = fmt.Sprintf("An unexpected error occurred at %s", monitor.URL)
+	default:
 		description = fmt.Sprintf("Received non-successful status code: %d %s", result.StatusCode, http.StatusText(result.StatusCode))
 	}
 
-	lastIncident := m.db.GetLastIncident(monitor.URL, incidentType)
+	lastIncident := m.db.GetLastIncident(monitor.ID, incidentType)
 	if lastIncident.IsExists() {
 		return false, incidentType // Incident already recorded
 	}
 
+	// The failure changed, e.g. from a DNS failure to a timeout, during the
+	// same outage
+	if m.retypeDownIncident(monitor, incidentType, description, attributes) {
+		return false, incidentType
+	}
+
 	inc := &models.Incident{
 		ID:          helper.GenerateRandomID(),
 		MonitorID:   monitor.ID,
@@ -163,32 +302,67 @@ func (m *UptimeMonitor) handleWebsiteDown(monitor *models.Monitor, result *net.C
 		Monitor:     *monitor,
 	}
 
-	if id, err := net.NotifyIncident(inc, incident.HIGH, incident.EventWebsiteDown, attributes); err == nil {
-		inc.IncidentID = id
-	}
-
 	now := time.Now()
 	monitor.LastDown = &now
-	m.db.DB.Create(inc)
+	m.openIncident(inc, incident.HIGH, incident.EventWebsiteDown, attributes)
 	log.Warn().Msgf(
 		"%s - New Incident detected! - Type: %s",
 		monitor.URL, inc.Type,
 	)
+	m.solveSlowIncidents(monitor)
 
 	return true, incidentType
 }
 
+// getDownDependency returns the first monitor this one depends on that is
+// currently down, or nil if every dependency is up.
+func (m *UptimeMonitor) getDownDependency(monitor *models.Monitor) *models.Monitor {
+	if len(monitor.DependsOn) == 0 {
+		return nil
+	}
+
+	parents, err := m.db.GetMonitorsByRef(monitor.DependsOn)
+	if err != nil {
+		log.Error().Err(err).Msgf("%s - failed to get dependencies", monitor.URL)
+		return nil
+	}
+
+	for _, parent := range parents {
+		if parent.IsUp != nil && !*parent.IsUp {
+			return &parent
+		}
+	}
+
+	return nil
+}
+
+// addAffectedMonitor records a suppressed dependent monitor on the parent's
+// open incidents.
+func (m *UptimeMonitor) addAffectedMonitor(parent *models.Monitor, monitor *models.Monitor) {
+	for _, incidentType := range incident.WebsiteDownTypes {
+		lastIncident := m.db.GetLastIncident(parent.ID, incidentType)
+		if lastIncident.IsNotExists() || slices.Contains(lastIncident.AffectedMonitors, monitor.ID) {
+			continue
+		}
+
+		lastIncident.AffectedMonitors = append(lastIncident.AffectedMonitors, monitor.ID)
+		if err := m.db.Upsert(lastIncident); err != nil {
+			log.Error().Err(err).Msgf("%s - failed to record affected monitor on incident %s", monitor.URL, lastIncident.ID)
+			continue
+		}
+		m.recordEvent(lastIncident, models.IncidentEventAffected, fmt.Sprintf("Failure of %s suppressed", monitor.URL))
+	}
+}
+
 func (m *UptimeMonitor) resolveIncidents(monitor *models.Monitor, incidentType incident.Type) bool {
 	// return true if incident solved; else false
 
 	now := time.Now()
-	lastIncident := m.db.GetLastIncident(monitor.URL, incidentType)
+	lastIncident := m.db.GetLastIncident(monitor.ID, incidentType)
 	if lastIncident.IsExists() {
-		lastIncident.SolvedAt = &now
 		monitor.LastUp = &now
-		m.db.Upsert(lastIncident)
+		m.solveIncident(lastIncident, fmt.Sprintf("Incident solved after %s", now.Sub(lastIncident.CreatedAt).Round(time.Second)))
 		log.Info().Msgf("%s - Incident Solved - Type: %s - Downtime: %s", monitor.URL, incidentType, time.Since(lastIncident.CreatedAt))
-		net.UpdateIncidentStatus(lastIncident, incident.Resolved)
 
 		return true
 	}
@@ -196,6 +370,61 @@ func (m *UptimeMonitor) resolveIncidents(monitor *models.Monitor, incidentType i
 	return false
 }
 
+// retypeDownIncident moves the open down incident of another failure type to
+// incidentType and reports it again to the master and the local notifiers,
+// like an expired certificate. It reports whether there was one.
+func (m *UptimeMonitor) retypeDownIncident(monitor *models.Monitor, incidentType incident.Type, description string, attributes map[string]any) bool {
+	for _, downType := range incident.WebsiteDownTypes {
+		if downType == incidentType {
+			continue
+		}
+
+		lastIncident := m.db.GetLastIncident(monitor.ID, downType)
+		if lastIncident.IsNotExists() {
+			continue
+		}
+
+		lastIncident.Type = incidentType
+		lastIncident.Description = description
+		lastIncident.Monitor = *monitor
+		result := notifyIncident(lastIncident, incident.HIGH, incident.EventWebsiteDown, attributes)
+		if err := m.db.Upsert(lastIncident); err != nil {
+			log.Error().Err(err).Msgf("%s - failed to update incident %s", monitor.URL, lastIncident.ID)
+			return false
+		}
+		m.recordEvent(lastIncident, models.IncidentEventUpdated, fmt.Sprintf("%s -> %s", downType, incidentType))
+		m.recordNotification(lastIncident, result)
+		log.Info().Msgf("%s - Incident Updated - Type: %s - New Type: %s", monitor.URL, downType, incidentType)
+
+		return true
+	}
+
+	return false
+}
+
+// solveSlowIncidents solves the incidents of a slow website that went down,
+// so one outage does not show two open incidents.
+func (m *UptimeMonitor) solveSlowIncidents(monitor *models.Monitor) {
+	for _, slowType := range incident.SlowTypes {
+		lastIncident := m.db.GetLastIncident(monitor.ID, slowType)
+		if lastIncident.IsNotExists() {
+			continue
+		}
+
+		m.solveIncident(lastIncident, "Website is down")
+		log.Info().Msgf("%s - Incident Solved - Type: %s - Website is down", monitor.URL, slowType)
+	}
+}
+
+func (m *UptimeMonitor) solveIncident(inc *models.Incident, message string) {
+	now := time.Now()
+	inc.SolvedAt = &now
+	inc.Status = incident.Resolved
+	m.db.Upsert(inc)
+	m.recordEvent(inc, models.IncidentEventResolved, message)
+	SyncIncidentStatus(m.db, inc, incident.Resolved, models.SystemActor)
+}
+
 func (m *UptimeMonitor) handleSSL(monitor *models.Monitor, result *net.CheckResults) bool {
 	// If SSL expiry date is not available, do nothing.
 	if result.SSLExpiredDate == nil {
@@ -203,7 +432,7 @@ func (m *UptimeMonitor) handleSSL(monitor *models.Monitor, result *net.CheckResu
 	}
 
 	now := time.Now()
-	lastIncident := m.db.GetLastIncident(monitor.URL, incident.SSLExpired)
+	lastIncident := m.db.GetLastIncident(monitor.ID, incident.SSLExpired)
 
 	attr := map[string]any{
 		"expired_date": result.SSLExpiredDate,
@@ -216,10 +445,11 @@ func (m *UptimeMonitor) handleSSL(monitor *models.Monitor, result *net.CheckResu
 		if lastIncident.IsExists() && lastIncident.Description == "Certificate almost expired" {
 			log.Warn().Msgf("%s - Certificate expired - [%s]", monitor.URL, result.SSLExpiredDate)
 			lastIncident.Description = "Certificate expired"
-			if id, err := net.NotifyIncident(lastIncident, incident.HIGH, incident.EventWebsiteCertificateExpired, attr); err == nil {
-				lastIncident.IncidentID = id
-			}
+			lastIncident.Monitor = *monitor
+			result := notifyIncident(lastIncident, incident.HIGH, incident.EventWebsiteCertificateExpired, attr)
 			m.db.Upsert(lastIncident)
+			m.recordEvent(lastIncident, models.IncidentEventUpdated, "Certificate almost expired -> Certificate expired")
+			m.recordNotification(lastIncident, result)
 			return true
 		}
 
@@ -233,10 +463,7 @@ func (m *UptimeMonitor) handleSSL(monitor *models.Monitor, result *net.CheckResu
 				Description: "Certificate expired",
 				Monitor:     *monitor,
 			}
-			if id, err := net.NotifyIncident(inc, incident.HIGH, incident.EventWebsiteCertificateExpired, attr); err == nil {
-				inc.IncidentID = id
-			}
-			m.db.DB.Create(inc)
+			m.openIncident(inc, incident.HIGH, incident.EventWebsiteCertificateExpired, attr)
 			return true
 		}
 
@@ -259,10 +486,7 @@ func (m *UptimeMonitor) handleSSL(monitor *models.Monitor, result *net.CheckResu
 				Description: "Certificate almost expired",
 				Monitor:     *monitor,
 			}
-			if id, err := net.NotifyIncident(inc, incident.INFO, incident.EventWebsiteCertificateExpired, attr); err == nil {
-				inc.IncidentID = id
-			}
-			m.db.DB.Create(inc)
+			m.openIncident(inc, incident.INFO, incident.EventWebsiteCertificateExpired, attr)
 			return true
 		}
 
@@ -276,7 +500,9 @@ func (m *UptimeMonitor) handleSSL(monitor *models.Monitor, result *net.CheckResu
 		// }
 
 		lastIncident.SolvedAt = &now
+		lastIncident.Status = incident.Resolved
 		m.db.Upsert(lastIncident)
+		m.recordEvent(lastIncident, models.IncidentEventResolved, "Certificate renewed")
 		log.Info().Msgf("%s - SSL Updated", monitor.URL)
 		return true
 	}
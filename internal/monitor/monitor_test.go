@@ -1,18 +1,21 @@
 package monitor
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
+	"uptime-go/internal/configuration"
 	"uptime-go/internal/incident"
 	"uptime-go/internal/models"
 	"uptime-go/internal/net"
 	"uptime-go/internal/net/database"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMonitorHandleWebsiteDown(t *testing.T) {
@@ -33,6 +36,14 @@ func TestMonitorHandleWebsiteDown(t *testing.T) {
 			expectedResult:       true,
 			expectedIncidentType: incident.Timeout,
 		},
+		{
+			name:                 "new connection refused incident",
+			monitor:              models.Monitor{},
+			checkResult:          net.CheckResults{FailureType: incident.ConnectionRefused},
+			err:                  errors.New("connection refused"),
+			expectedResult:       true,
+			expectedIncidentType: incident.ConnectionRefused,
+		},
 		{
 			name:        "incident already exists",
 			monitor:     models.Monitor{},
@@ -212,7 +223,7 @@ func TestHandleSSL(t *testing.T) {
 			assert.Equal(t, tc.expectedResult, result)
 
 			if tc.expectedDesc != "" {
-				lastIncident := uptimeMonitor.db.GetLastIncident(tc.monitor.URL, incident.SSLExpired)
+				lastIncident := uptimeMonitor.db.GetLastIncident(tc.monitor.ID, incident.SSLExpired)
 				assert.Equal(t, tc.expectedDesc, lastIncident.Description)
 			}
 		})
@@ -263,8 +274,249 @@ func TestCheckWebsite(t *testing.T) {
 		assert.False(t, *monitor.IsUp)
 		assert.Equal(t, http.StatusInternalServerError, *monitor.StatusCode)
 
-		lastIncident := uptimeMonitor.db.GetLastIncident(monitor.URL, incident.UnexpectedStatusCode)
+		lastIncident := uptimeMonitor.db.GetLastIncident(monitor.ID, incident.UnexpectedStatusCode)
 		assert.True(t, lastIncident.IsExists())
 		assert.Equal(t, "Received non-successful status code: 500 Internal Server Error", lastIncident.Description)
 	})
 }
+
+func TestCheckWebsiteDuringMaintenance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	db, _ := database.InitializeTestDatabase()
+	uptimeMonitor, _ := NewUptimeMonitor(db, nil)
+	monitor := &models.Monitor{
+		URL:                   server.URL,
+		Interval:              1 * time.Minute,
+		ResponseTimeThreshold: 5 * time.Second,
+	}
+	db.DB.Create(monitor)
+
+	start := time.Now().Add(-time.Minute)
+	end := time.Now().Add(time.Hour)
+	db.DB.Create(&models.Maintenance{
+		ID:       "deploy",
+		Name:     "deploy",
+		Monitors: []string{server.URL},
+		StartsAt: &start,
+		EndsAt:   &end,
+	})
+
+	uptimeMonitor.checkWebsite(monitor)
+
+	lastIncident := uptimeMonitor.db.GetLastIncident(monitor.ID, incident.UnexpectedStatusCode)
+	assert.True(t, lastIncident.IsNotExists())
+
+	var history models.MonitorHistory
+	db.DB.Where("monitor_id = ?", monitor.ID).First(&history)
+	assert.False(t, history.IsUp)
+	assert.True(t, history.InMaintenance)
+}
+
+func TestCheckWebsiteWithDownDependency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	db, _ := database.InitializeTestDatabase()
+	uptimeMonitor, _ := NewUptimeMonitor(db, nil)
+
+	isUp := false
+	parent := &models.Monitor{
+		ID:        "parent",
+		URL:       "https://lb.example.com",
+		IsUp:      &isUp,
+		Incidents: []models.Incident{{ID: "lb-down", Type: incident.Timeout}},
+	}
+	db.DB.Create(parent)
+
+	child := &models.Monitor{
+		ID:                    "child",
+		URL:                   server.URL,
+		Interval:              1 * time.Minute,
+		ResponseTimeThreshold: 5 * time.Second,
+		DependsOn:             []string{parent.URL},
+	}
+	db.DB.Create(child)
+
+	// Monitors sharing a URL are told apart by ID
+	sibling := &models.Monitor{
+		ID:                    "child-eu",
+		URL:                   server.URL,
+		Interval:              1 * time.Minute,
+		ResponseTimeThreshold: 5 * time.Second,
+		DependsOn:             []string{parent.ID},
+	}
+	db.DB.Create(sibling)
+
+	uptimeMonitor.checkWebsite(child)
+	uptimeMonitor.checkWebsite(sibling)
+
+	childIncident := uptimeMonitor.db.GetLastIncident(child.ID, incident.UnexpectedStatusCode)
+	assert.True(t, childIncident.IsNotExists())
+
+	parentIncident := uptimeMonitor.db.GetLastIncident(parent.ID, incident.Timeout)
+	assert.Equal(t, []string{child.ID, sibling.ID}, parentIncident.AffectedMonitors)
+
+	var history models.MonitorHistory
+	db.DB.Where("monitor_id = ?", child.ID).First(&history)
+	assert.True(t, history.Suppressed)
+}
+
+func TestIncidentTimeline(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	db, _ := database.InitializeTestDatabase()
+	uptimeMonitor, _ := NewUptimeMonitor(db, nil)
+	monitor := &models.Monitor{
+		ID:                    "timeline",
+		URL:                   server.URL,
+		Interval:              1 * time.Minute,
+		ResponseTimeThreshold: 5 * time.Second,
+	}
+	db.DB.Create(monitor)
+
+	uptimeMonitor.checkWebsite(monitor)
+	lastIncident := uptimeMonitor.db.GetLastIncident(monitor.ID, incident.UnexpectedStatusCode)
+
+	status = http.StatusOK
+	uptimeMonitor.checkWebsite(monitor)
+
+	inc, err := db.GetIncident(lastIncident.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, incident.Resolved, inc.Status)
+
+	var eventTypes []string
+	for _, event := range inc.Events {
+		eventTypes = append(eventTypes, event.Type)
+	}
+
+	// The master is not configured in tests, so notifications fail
+	assert.Equal(t, []string{
+		models.IncidentEventCreated,
+		models.IncidentEventNotifyFailed,
+		models.IncidentEventResolved,
+		models.IncidentEventStatusFailed,
+	}, eventTypes)
+}
+
+func TestHandleWebsiteDownRetypesIncident(t *testing.T) {
+	var payloads []map[string]any
+	notifier := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		json.NewDecoder(r.Body).Decode(&payload)
+		payloads = append(payloads, payload)
+	}))
+	defer notifier.Close()
+
+	previous := configuration.Config
+	t.Cleanup(func() { configuration.Config = previous })
+	configuration.Config = configuration.AppConfig{
+		Standalone: true,
+		Notifiers:  []configuration.NotifierConfig{{Name: "test", URL: notifier.URL}},
+	}
+
+	db, _ := database.InitializeTestDatabase()
+	uptimeMonitor, _ := NewUptimeMonitor(db, nil)
+	monitor := &models.Monitor{
+		ID:        "retype",
+		URL:       "https://example.com",
+		Incidents: []models.Incident{{ID: "dns", Type: incident.DNSFailure}},
+	}
+	db.DB.Create(monitor)
+
+	created, incidentType := uptimeMonitor.handleWebsiteDown(monitor, &net.CheckResults{FailureType: incident.Timeout}, os.ErrDeadlineExceeded)
+	assert.False(t, created)
+	assert.Equal(t, incident.Timeout, incidentType)
+
+	assert.True(t, uptimeMonitor.db.GetLastIncident(monitor.ID, incident.DNSFailure).IsNotExists())
+	lastIncident := uptimeMonitor.db.GetLastIncident(monitor.ID, incident.Timeout)
+	assert.Equal(t, "dns", lastIncident.ID)
+	assert.Equal(t, "Request timed out: https://example.com", lastIncident.Description)
+	assert.Nil(t, monitor.LastUp)
+
+	// The notifiers receive the incident with its new type
+	require.Len(t, payloads, 1)
+	assert.Equal(t, "Request timed out: https://example.com", payloads[0]["message"])
+	assert.Contains(t, payloads[0]["tags"], "timeout")
+
+	var events []models.IncidentEvent
+	db.DB.Where("incident_id = ?", "dns").Order("created_at").Find(&events)
+	require.Len(t, events, 2)
+	assert.Equal(t, models.IncidentEventUpdated, events[0].Type)
+	assert.Equal(t, "dns_failure -> timeout", events[0].Message)
+	assert.Equal(t, models.IncidentEventNotified, events[1].Type)
+
+	// The same failure again changes nothing
+	created, _ = uptimeMonitor.handleWebsiteDown(monitor, &net.CheckResults{FailureType: incident.Timeout}, os.ErrDeadlineExceeded)
+	assert.False(t, created)
+	assert.Len(t, payloads, 1)
+	var count int64
+	db.DB.Model(&models.Incident{}).Where("monitor_id = ?", monitor.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestCheckNow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	db, _ := database.InitializeTestDatabase()
+	monitor := &models.Monitor{
+		ID:                    "abc123",
+		URL:                   server.URL,
+		Name:                  "website",
+		Interval:              1 * time.Minute,
+		ResponseTimeThreshold: 5 * time.Second,
+	}
+	db.DB.Create(monitor)
+	uptimeMonitor, _ := NewUptimeMonitor(db, []*models.Monitor{monitor})
+
+	events, unsubscribe := uptimeMonitor.Events().Subscribe()
+	defer unsubscribe()
+
+	check, err := uptimeMonitor.CheckNow("website")
+	assert.NoError(t, err)
+	assert.False(t, check.IsUp)
+	assert.Equal(t, http.StatusServiceUnavailable, check.StatusCode)
+	assert.Equal(t, incident.UnexpectedStatusCode, check.FailureType)
+
+	lastIncident := db.GetLastIncident(monitor.ID, incident.UnexpectedStatusCode)
+	assert.True(t, lastIncident.IsExists())
+
+	var published []string
+	for len(events) > 0 {
+		published = append(published, (<-events).Type)
+	}
+	assert.Contains(t, published, "check")
+
+	_, err = uptimeMonitor.CheckNow("missing")
+	assert.ErrorIs(t, err, ErrMonitorNotFound)
+}
+
+func TestCheckOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	check := CheckOnce(&models.Monitor{
+		URL:                   server.URL,
+		ResponseTimeThreshold: 5 * time.Second,
+		DegradedThreshold:     10 * time.Millisecond,
+	})
+
+	assert.True(t, check.IsUp)
+	assert.True(t, check.IsDegraded)
+	assert.Equal(t, "DEGRADED", check.Status)
+}
@@ -0,0 +1,54 @@
+package net
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"uptime-go/internal/incident"
+)
+
+// ClassifyError maps a request error to the incident type describing the
+// failure. Errors that cannot be classified are reported as
+// incident.UnexpectedStatusCode.
+func ClassifyError(err error) incident.Type {
+	var dnsErr *net.DNSError
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certInvalidErr x509.CertificateInvalidError
+	var recordHeaderErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+
+	switch {
+	case err == nil:
+		return ""
+	// DNS errors also report timeouts, so they are checked first
+	case errors.As(err, &dnsErr):
+		return incident.DNSFailure
+	case os.IsTimeout(err) || errors.Is(err, os.ErrDeadlineExceeded):
+		return incident.Timeout
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return incident.ConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		return incident.ConnectionReset
+	case errors.As(err, &certErr) ||
+		errors.As(err, &unknownAuthorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &certInvalidErr):
+		return incident.CertificateInvalid
+	// net/http turns a plain HTTP answer to a TLS handshake into a string
+	// error and TLS alerts received from the server are not exported
+	case errors.As(err, &recordHeaderErr) ||
+		errors.As(err, &alertErr) ||
+		strings.Contains(err.Error(), "server gave HTTP response to HTTPS client") ||
+		strings.Contains(err.Error(), "tls: "):
+		return incident.TLSHandshakeFailure
+	default:
+		return incident.UnexpectedStatusCode
+	}
+}
@@ -0,0 +1,84 @@
+package net
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+	"uptime-go/internal/incident"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected incident.Type
+	}{
+		{"no error", nil, ""},
+		{"dns", &net.DNSError{Err: "no such host", Name: "example.invalid", IsNotFound: true}, incident.DNSFailure},
+		{"dns timeout", &net.DNSError{Err: "i/o timeout", IsTimeout: true}, incident.DNSFailure},
+		{"timeout", os.ErrDeadlineExceeded, incident.Timeout},
+		{"refused", &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, incident.ConnectionRefused},
+		{"reset", &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, incident.ConnectionReset},
+		{"eof", fmt.Errorf("get: %w", io.EOF), incident.ConnectionReset},
+		{"unknown authority", x509.UnknownAuthorityError{}, incident.CertificateInvalid},
+		{"unclassified", errors.New("something else"), incident.UnexpectedStatusCode},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ClassifyError(tc.err))
+		})
+	}
+}
+
+func TestCheckWebsiteFailureType(t *testing.T) {
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer plain.Close()
+
+	secure := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer secure.Close()
+
+	// Nothing listens on a port freed by closing its listener
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := "http://" + listener.Addr().String()
+	listener.Close()
+
+	testCases := []struct {
+		name     string
+		url      string
+		expected incident.Type
+	}{
+		{"status code", plain.URL, incident.UnexpectedStatusCode},
+		{"connection refused", closed, incident.ConnectionRefused},
+		{"tls handshake", strings.Replace(plain.URL, "http://", "https://", 1), incident.TLSHandshakeFailure},
+		{"untrusted certificate", strings.Replace(secure.URL, "127.0.0.1", "localhost", 1), incident.CertificateInvalid},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			nc := NetworkConfig{URL: tc.url, Timeout: time.Second}
+			result, _ := nc.CheckWebsite()
+
+			assert.False(t, result.IsUp)
+			assert.Equal(t, tc.expected, result.FailureType)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// backend holds the SQL that differs between the supported databases.
+type backend interface {
+	dialector(dsn string) gorm.Dialector
+	// hasTag is a condition on a JSON array column holding the value of its
+	// placeholder.
+	hasTag(column string) string
+	// hasLabel is a condition on a JSON object column holding the key and
+	// value of its placeholders.
+	hasLabel(column string) string
+	// day formats a time column as its UTC day, YYYY-MM-DD.
+	day(column string) string
+}
+
+// IsPostgres reports whether dsn selects PostgreSQL, either as a
+// postgres:// URL or as key/value settings. Anything else is the path of a
+// SQLite database file, so settings holding a path, such as the directory of
+// a Unix socket, must use the URL form.
+func IsPostgres(dsn string) bool {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		return true
+	}
+
+	fields := strings.Fields(dsn)
+	if len(fields) == 0 || strings.ContainsAny(dsn, `/\`) {
+		return false
+	}
+
+	for _, field := range fields {
+		if key, _, ok := strings.Cut(field, "="); !ok || key == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newBackend(dsn string) backend {
+	if IsPostgres(dsn) {
+		return postgresBackend{}
+	}
+
+	return sqliteBackend{}
+}
+
+// RedactDSN hides the password of a PostgreSQL DSN for logging.
+func RedactDSN(dsn string) string {
+	if !IsPostgres(dsn) {
+		return dsn
+	}
+
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" {
+		return u.Redacted()
+	}
+
+	fields := strings.Fields(dsn)
+	for i, field := range fields {
+		if strings.HasPrefix(field, "password=") {
+			fields[i] = "password=xxxxx"
+		}
+	}
+
+	return strings.Join(fields, " ")
+}
+
+type sqliteBackend struct{}
+
+func (sqliteBackend) dialector(dsn string) gorm.Dialector {
+	return sqlite.Open(dsn + "?_journal_mode=WAL&_pragma=foreign_keys")
+}
+
+func (sqliteBackend) hasTag(column string) string {
+	return "EXISTS (SELECT 1 FROM json_each(" + column + ") WHERE value = ?)"
+}
+
+func (sqliteBackend) hasLabel(column string) string {
+	return "EXISTS (SELECT 1 FROM json_each(" + column + ") WHERE key = ? AND value = ?)"
+}
+
+func (sqliteBackend) day(column string) string {
+	return "DATE(" + column + ")"
+}
+
+type postgresBackend struct{}
+
+func (postgresBackend) dialector(dsn string) gorm.Dialector {
+	return postgres.Open(dsn)
+}
+
+// JSON columns are stored as text, and as NULL when empty, which matches
+// nothing.
+func (postgresBackend) hasTag(column string) string {
+	return column + "::jsonb @> jsonb_build_array(?::text)"
+}
+
+func (postgresBackend) hasLabel(column string) string {
+	return column + "::jsonb @> jsonb_build_object(?::text, ?::text)"
+}
+
+func (postgresBackend) day(column string) string {
+	return "TO_CHAR(" + column + " AT TIME ZONE 'UTC', 'YYYY-MM-DD')"
+}
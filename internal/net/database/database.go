@@ -3,40 +3,69 @@ package database
 import (
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"time"
+	"uptime-go/internal/helper"
 	"uptime-go/internal/incident"
 	"uptime-go/internal/models"
 
 	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type Database struct {
-	DB    *gorm.DB
-	mutex sync.RWMutex
+	DB      *gorm.DB
+	mutex   sync.RWMutex
+	backend backend
+	path    string // SQLite database file, empty for in-memory and PostgreSQL databases
 }
 
-func New(dbPath string) (*Database, error) {
+// New opens the database and migrates its schema.
+func New(dsn string) (*Database, error) {
+	db, err := Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Migrate(); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Open connects to the PostgreSQL database of dsn, or opens the SQLite
+// database file at dsn, creating it if needed, without migrating its schema.
+func Open(dsn string) (*Database, error) {
+	backend := newBackend(dsn)
+
+	path := ""
+	if _, ok := backend.(sqliteBackend); ok && dsn != ":memory:" {
+		path = dsn
+	}
+
 	// Check if the database file exists, if not create it
-	if _, errStat := os.Stat(dbPath); dbPath != ":memory:" && errStat != nil {
+	if _, errStat := os.Stat(path); path != "" && errStat != nil {
 		if !os.IsNotExist(errStat) {
 			return nil, errStat
 		}
 
-		file, errCreate := os.Create(dbPath)
+		file, errCreate := os.Create(path)
 		if errCreate != nil {
 			return nil, fmt.Errorf("failed to create database file: %w", errCreate)
 		}
 		file.Close()
 	}
 
-	log.Info().Str("database", dbPath).Msg("Connectiong to database...")
+	log.Info().Str("database", RedactDSN(dsn)).Msg("Connectiong to database...")
 
-	// Open the database connection using GORM and SQLite with connection pool configuration
-	gormDB, errOpen := gorm.Open(sqlite.Open(dbPath+"?_journal_mode=WAL&_pragma=foreign_keys"), &gorm.Config{})
+	// Open the database connection using GORM with connection pool configuration
+	gormDB, errOpen := gorm.Open(backend.dialector(dsn), &gorm.Config{})
 	if errOpen != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", errOpen)
 	}
@@ -53,34 +82,69 @@ func New(dbPath string) (*Database, error) {
 	sqlDB.SetConnMaxLifetime(time.Hour)
 	sqlDB.SetConnMaxIdleTime(30 * time.Minute)
 
-	// Migrate the schema
-	if errMigrate := gormDB.AutoMigrate(
-		&models.Monitor{},
-		&models.MonitorHistory{},
-		&models.Incident{},
-	); errMigrate != nil {
-		return nil, fmt.Errorf("failed to migrate database schema: %w", errMigrate)
-	}
-
-	return &Database{DB: gormDB}, nil
+	return &Database{DB: gormDB, backend: backend, path: path}, nil
 }
 
+// InitializeTestDatabase returns an empty in-memory SQLite database, or an
+// empty schema in the PostgreSQL database of UPTIME_TEST_POSTGRES_DSN when
+// it is set.
 func InitializeTestDatabase() (*Database, error) {
+	if dsn := os.Getenv("UPTIME_TEST_POSTGRES_DSN"); dsn != "" {
+		return initializeTestPostgres(dsn)
+	}
+
 	db, err := gorm.Open(sqlite.Open("file::memory:?_journal_mode=WAL&_pragma=foreign_keys"))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	if err := db.AutoMigrate(
-		&models.Monitor{},
-		&models.MonitorHistory{},
-		&models.Incident{},
-	); err != nil {
-		return nil, fmt.Errorf("failed to migrate database schema: %w", err)
+	database := &Database{DB: db, backend: sqliteBackend{}}
+	if _, err := database.Migrate(); err != nil {
+		return nil, err
 	}
 
-	return &Database{DB: db}, nil
+	return database, nil
+}
+
+// initializeTestPostgres creates a schema of its own for every test
+// database, so packages tested in parallel do not share tables.
+func initializeTestPostgres(dsn string) (*Database, error) {
+	admin, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	err = admin.Exec("CREATE SCHEMA " + schema).Error
+	if sqlDB, errSQL := admin.DB(); errSQL == nil {
+		sqlDB.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create test schema: %w", err)
+	}
+
+	if strings.Contains(dsn, "://") {
+		separator := "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+		dsn += separator + "search_path=" + schema
+	} else {
+		dsn += " search_path=" + schema
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	database := &Database{DB: db, backend: postgresBackend{}}
+	if _, err := database.Migrate(); err != nil {
+		return nil, err
+	}
+
+	return database, nil
 }
 
 func (db *Database) UpsertRecord(record any, column string, updateColumn *[]string) error {
@@ -122,24 +186,147 @@ func (db *Database) GetAllMonitors() ([]models.Monitor, error) {
 	return monitors, nil
 }
 
-func (db *Database) GetMonitorWithHistories(url string, limit int) (*models.Monitor, error) {
+// GetMonitor returns a monitor by ID or name, or gorm.ErrRecordNotFound if
+// there is none.
+func (db *Database) GetMonitor(idOrName string) (*models.Monitor, error) {
 	var monitor models.Monitor
 	db.mutex.RLock()
 	defer db.mutex.RUnlock()
 
-	if err := db.DB.
-		Preload("Histories", func(db *gorm.DB) *gorm.DB {
-			return db.Order("monitor_histories.created_at DESC").Limit(limit)
-		}).
-		Where("url = ?", url).
-		Find(&monitor).Error; err != nil {
-		return nil, fmt.Errorf("failed to get monitor with histories for URL %s: %w", url, err)
+	if err := db.DB.Where("id = ? OR name = ?", idOrName, idOrName).First(&monitor).Error; err != nil {
+		return nil, err
 	}
 
 	return &monitor, nil
 }
 
-func (db *Database) GetLastIncident(url string, incidentType incident.Type) *models.Incident {
+// AdoptMonitorIDs moves the histories and incidents of monitors stored under
+// another ID to the IDs of the configured monitors. A configured monitor
+// without a record adopts the record of its URL, when a single one is left
+// that no configured monitor uses, e.g. after upgrading from random IDs or
+// assigning an id to a monitor.
+func (db *Database) AdoptMonitorIDs(monitors []*models.Monitor) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	configured := map[string]bool{}
+	for _, monitor := range monitors {
+		configured[monitor.ID] = true
+	}
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		for _, monitor := range monitors {
+			var existing []models.Monitor
+			if err := tx.Where("id = ? OR url = ?", monitor.ID, monitor.URL).Find(&existing).Error; err != nil {
+				return fmt.Errorf("failed to get monitors of %s: %w", monitor.URL, err)
+			}
+
+			var candidates []string
+			for _, record := range existing {
+				if record.ID == monitor.ID {
+					candidates = nil
+					break
+				}
+				if !configured[record.ID] {
+					candidates = append(candidates, record.ID)
+				}
+			}
+			if len(candidates) != 1 {
+				continue
+			}
+
+			previous := candidates[0]
+			for _, update := range []struct {
+				model  any
+				column string
+			}{
+				{&models.Monitor{}, "id"},
+				{&models.MonitorHistory{}, "monitor_id"},
+				{&models.Incident{}, "monitor_id"},
+			} {
+				if err := tx.Model(update.model).Where(update.column+" = ?", previous).Update(update.column, monitor.ID).Error; err != nil {
+					return fmt.Errorf("failed to move monitor %s to %s: %w", previous, monitor.ID, err)
+				}
+			}
+
+			log.Info().Str("url", monitor.URL).Str("from", previous).Str("to", monitor.ID).Msg("monitor ID migrated")
+		}
+
+		return nil
+	})
+}
+
+// GetMonitorsByRef returns the monitors referred to by refs, monitor IDs or
+// URLs. A URL refers to every monitor checking it.
+func (db *Database) GetMonitorsByRef(refs []string) ([]models.Monitor, error) {
+	urls := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		urls = append(urls, helper.NormalizeURL(ref))
+	}
+
+	var monitors []models.Monitor
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	if err := db.DB.Where("id IN ? OR url IN ?", refs, urls).Order("id").Find(&monitors).Error; err != nil {
+		return nil, fmt.Errorf("failed to get monitors: %w", err)
+	}
+	return monitors, nil
+}
+
+// GetRecentHistories returns the latest histories of a monitor, newest first.
+func (db *Database) GetRecentHistories(monitorID string, limit int) ([]models.MonitorHistory, error) {
+	var histories []models.MonitorHistory
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	if err := db.DB.
+		Where("monitor_id = ?", monitorID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&histories).Error; err != nil {
+		return nil, fmt.Errorf("failed to get histories for monitor %s: %w", monitorID, err)
+	}
+
+	return histories, nil
+}
+
+// GetRecentSuccessfulHistories returns the latest successful histories of a
+// monitor, newest first.
+func (db *Database) GetRecentSuccessfulHistories(monitorID string, limit int) ([]models.MonitorHistory, error) {
+	var histories []models.MonitorHistory
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	if err := db.DB.
+		Where("monitor_id = ? AND is_up = ?", monitorID, true).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&histories).Error; err != nil {
+		return nil, fmt.Errorf("failed to get successful histories for monitor %s: %w", monitorID, err)
+	}
+
+	return histories, nil
+}
+
+// GetHistoriesSince returns the histories of a monitor created after since,
+// oldest first.
+func (db *Database) GetHistoriesSince(monitorID string, since time.Time) ([]models.MonitorHistory, error) {
+	var histories []models.MonitorHistory
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	if err := db.DB.
+		Where("monitor_id = ? AND created_at >= ?", monitorID, since).
+		Order("created_at").
+		Find(&histories).Error; err != nil {
+		return nil, fmt.Errorf("failed to get histories for monitor %s: %w", monitorID, err)
+	}
+
+	return histories, nil
+}
+
+func (db *Database) GetLastIncident(monitorID string, incidentType incident.Type) *models.Incident {
 	var incident models.Incident
 
 	db.mutex.RLock()
@@ -147,10 +334,42 @@ func (db *Database) GetLastIncident(url string, incidentType incident.Type) *mod
 
 	db.DB.Joins("Monitor").
 		Select("incidents.*").
-		Where("Monitor.url = ? AND incidents.type = ? AND incidents.solved_at IS NULL", url, incidentType).
+		Where("incidents.monitor_id = ? AND incidents.type = ? AND incidents.solved_at IS NULL", monitorID, incidentType).
 		Order("incidents.created_at DESC").
 		Limit(1).
 		Find(&incident)
 
 	return &incident
 }
+
+// GetUptime returns the percentage of successful checks for a monitor since
+// the given time, or nil when there are no checks in that period. Checks
+// recorded during maintenance are left out when excludeMaintenance is set.
+func (db *Database) GetUptime(monitorID string, since time.Time, excludeMaintenance bool) (*float64, error) {
+	var stats struct {
+		Total int64
+		Up    int64
+	}
+
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	query := db.DB.Model(&models.MonitorHistory{}).
+		Select("COUNT(*) AS total, COALESCE(SUM(CASE WHEN is_up THEN 1 ELSE 0 END), 0) AS up").
+		Where("monitor_id = ? AND created_at >= ?", monitorID, since)
+
+	if excludeMaintenance {
+		query = query.Where("in_maintenance = ?", false)
+	}
+
+	if err := query.Scan(&stats).Error; err != nil {
+		return nil, fmt.Errorf("failed to calculate uptime for monitor %s: %w", monitorID, err)
+	}
+
+	if stats.Total == 0 {
+		return nil, nil
+	}
+
+	uptime := float64(stats.Up) / float64(stats.Total) * 100
+	return &uptime, nil
+}
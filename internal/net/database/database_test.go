@@ -0,0 +1,178 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestMigrateUniqueURL(t *testing.T) {
+	// Monitors table of databases created before monitors had stable IDs
+	type legacyMonitor struct {
+		ID  string `gorm:"primaryKey"`
+		URL string `gorm:"unique"`
+	}
+
+	path := filepath.Join(t.TempDir(), "uptime.db")
+	legacy, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, legacy.Table("monitors").AutoMigrate(&legacyMonitor{}))
+	require.NoError(t, legacy.Table("monitors").Create(&legacyMonitor{ID: "1a2b3c4d", URL: "https://example.com"}).Error)
+
+	db, err := New(path)
+	require.NoError(t, err)
+	assert.False(t, db.DB.Migrator().HasConstraint(&models.Monitor{}, "uni_monitors_url"))
+	assert.NoError(t, db.DB.Create(&models.Monitor{ID: "second", URL: "https://example.com"}).Error)
+
+	var count int64
+	db.DB.Model(&models.Monitor{}).Where("url = ?", "https://example.com").Count(&count)
+	assert.Equal(t, int64(2), count)
+
+	// The existing database was backed up before migrating
+	backups, _ := filepath.Glob(path + ".*.bak")
+	assert.Len(t, backups, 1)
+}
+
+func TestAdoptMonitorIDs(t *testing.T) {
+	db, _ := InitializeTestDatabase()
+	db.DB.Create(&models.Monitor{
+		ID:        "1a2b3c4d",
+		URL:       "https://example.com",
+		Histories: []models.MonitorHistory{{IsUp: true}},
+		Incidents: []models.Incident{{ID: "open", Type: incident.Timeout}},
+	})
+	db.DB.Create(&models.Monitor{ID: "kept", URL: "https://kept.example.com"})
+
+	monitors := []*models.Monitor{
+		{ID: "uuid", URL: "https://example.com"},
+		{ID: "second", URL: "https://example.com"}, // Nothing left to adopt
+		{ID: "kept", URL: "https://kept.example.com"},
+	}
+	require.NoError(t, db.AdoptMonitorIDs(monitors))
+
+	var ids []string
+	db.DB.Model(&models.Monitor{}).Order("id").Pluck("id", &ids)
+	assert.Equal(t, []string{"kept", "uuid"}, ids)
+
+	histories, err := db.GetRecentHistories("uuid", 10)
+	require.NoError(t, err)
+	assert.Len(t, histories, 1)
+	assert.True(t, db.GetLastIncident("uuid", incident.Timeout).IsExists())
+
+	// Adopting again changes nothing
+	require.NoError(t, db.AdoptMonitorIDs(monitors))
+	db.DB.Model(&models.Monitor{}).Order("id").Pluck("id", &ids)
+	assert.Equal(t, []string{"kept", "uuid"}, ids)
+}
+
+func TestGetUptime(t *testing.T) {
+	db, _ := InitializeTestDatabase()
+	db.DB.Create(&models.Monitor{ID: "monitor", URL: "https://example.com"})
+	since := time.Now().Add(-time.Hour)
+
+	uptime, err := db.GetUptime("monitor", since, true)
+	assert.NoError(t, err)
+	assert.Nil(t, uptime)
+
+	db.DB.Create(&[]models.MonitorHistory{
+		{MonitorID: "monitor", IsUp: true},
+		{MonitorID: "monitor", IsUp: false},
+		{MonitorID: "monitor", IsUp: false, InMaintenance: true},
+	})
+
+	uptime, _ = db.GetUptime("monitor", since, true)
+	assert.Equal(t, 50.0, *uptime)
+
+	uptime, _ = db.GetUptime("monitor", since, false)
+	assert.InDelta(t, 33.33, *uptime, 0.01)
+}
+
+func TestMigrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uptime.db")
+
+	db, err := New(path)
+	require.NoError(t, err)
+
+	version, err := db.SchemaVersion()
+	require.NoError(t, err)
+	assert.Equal(t, LatestSchemaVersion(), version)
+
+	statuses, err := db.MigrationStatus()
+	require.NoError(t, err)
+	require.Len(t, statuses, LatestSchemaVersion())
+	for _, status := range statuses {
+		assert.NotNil(t, status.AppliedAt, "migration %d", status.Version)
+	}
+
+	// A fresh database is not backed up
+	backups, _ := filepath.Glob(path + ".*.bak")
+	assert.Empty(t, backups)
+
+	applied, err := db.Migrate()
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	t.Run("rollback", func(t *testing.T) {
+		require.NoError(t, db.DB.Create(&models.Monitor{ID: "first", URL: "https://example.com"}).Error)
+		require.NoError(t, db.DB.Create(&models.Monitor{ID: "second", URL: "https://example.com"}).Error)
+
+		_, err := db.Rollback(1, true)
+		assert.ErrorContains(t, err, "several monitors use https://example.com")
+		version, _ := db.SchemaVersion()
+		assert.Equal(t, 2, version)
+
+		require.NoError(t, db.DB.Delete(&models.Monitor{ID: "second"}).Error)
+		reverted, err := db.Rollback(1, true)
+		require.NoError(t, err)
+		require.Len(t, reverted, 1)
+		assert.Equal(t, 2, reverted[0].Version)
+		assert.Error(t, db.DB.Create(&models.Monitor{ID: "second", URL: "https://example.com"}).Error)
+
+		// Both rollbacks backed the database up
+		backups, _ := filepath.Glob(path + ".*.bak")
+		assert.Len(t, backups, 2)
+
+		applied, err := db.Migrate()
+		require.NoError(t, err)
+		require.Len(t, applied, 1)
+		assert.Equal(t, 2, applied[0].Version)
+		assert.NoError(t, db.DB.Create(&models.Monitor{ID: "second", URL: "https://example.com"}).Error)
+	})
+
+	t.Run("newer schema", func(t *testing.T) {
+		require.NoError(t, db.DB.Create(&SchemaMigration{Version: LatestSchemaVersion() + 1, Description: "From the future"}).Error)
+		t.Cleanup(func() { db.DB.Delete(&SchemaMigration{Version: LatestSchemaVersion() + 1}) })
+
+		_, err := db.Migrate()
+		assert.ErrorIs(t, err, ErrSchemaTooNew)
+		_, err = db.Rollback(0, true)
+		assert.ErrorIs(t, err, ErrSchemaTooNew)
+	})
+}
+
+func TestBackupPostgres(t *testing.T) {
+	db := &Database{backend: postgresBackend{}}
+
+	_, err := db.Backup()
+	assert.ErrorIs(t, err, ErrBackupUnsupported)
+}
+
+func TestBackupsArePruned(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "uptime.db"))
+	require.NoError(t, err)
+
+	for range MaxDatabaseBackups + 2 {
+		_, err := db.Backup()
+		require.NoError(t, err)
+	}
+
+	backups, _ := filepath.Glob(db.path + ".*.bak")
+	assert.Len(t, backups, MaxDatabaseBackups)
+}
@@ -0,0 +1,238 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrIncidentSolved = errors.New("incident is already solved")
+
+// AddIncidentEvent appends an entry to the timeline of an incident.
+func (db *Database) AddIncidentEvent(incidentID string, eventType string, message string, actor string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	return addIncidentEvent(db.DB, incidentID, eventType, message, actor)
+}
+
+func addIncidentEvent(tx *gorm.DB, incidentID string, eventType string, message string, actor string) error {
+	event := &models.IncidentEvent{
+		IncidentID: incidentID,
+		Type:       eventType,
+		Message:    message,
+		Actor:      actor,
+	}
+
+	if err := tx.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to record %s event for incident %s: %w", eventType, incidentID, err)
+	}
+
+	return nil
+}
+
+// GetIncident returns an incident with its monitor and timeline, or
+// gorm.ErrRecordNotFound if it does not exist.
+func (db *Database) GetIncident(id string) (*models.Incident, error) {
+	var inc models.Incident
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	if err := db.DB.
+		Preload("Monitor").
+		Preload("Events", func(db *gorm.DB) *gorm.DB {
+			return db.Order("incident_events.created_at")
+		}).
+		Where("id = ?", id).
+		First(&inc).Error; err != nil {
+		return nil, err
+	}
+
+	return &inc, nil
+}
+
+// AcknowledgeIncident marks an open incident as under investigation.
+func (db *Database) AcknowledgeIncident(id string, by string, note string) (*models.Incident, error) {
+	message := "Incident acknowledged"
+	if note != "" {
+		message = note
+	}
+
+	err := db.updateIncident(id, func(tx *gorm.DB, inc *models.Incident) error {
+		if inc.IsSolved() {
+			return ErrIncidentSolved
+		}
+
+		now := time.Now()
+		if err := tx.Model(inc).Updates(map[string]any{
+			"status":          incident.OnInvestigation,
+			"acknowledged_at": &now,
+			"acknowledged_by": by,
+		}).Error; err != nil {
+			return err
+		}
+
+		return addIncidentEvent(tx, inc.ID, models.IncidentEventAcknowledged, message, by)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetIncident(id)
+}
+
+// AddIncidentNote attaches a free-form note to the timeline of an incident.
+func (db *Database) AddIncidentNote(id string, by string, note string) (*models.Incident, error) {
+	err := db.updateIncident(id, func(tx *gorm.DB, inc *models.Incident) error {
+		return addIncidentEvent(tx, inc.ID, models.IncidentEventNote, note, by)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetIncident(id)
+}
+
+// MarkIncidentFalsePositive closes an incident as a false positive.
+func (db *Database) MarkIncidentFalsePositive(id string, by string, note string) (*models.Incident, error) {
+	message := "Incident marked as false positive"
+	if note != "" {
+		message = note
+	}
+
+	err := db.updateIncident(id, func(tx *gorm.DB, inc *models.Incident) error {
+		updates := map[string]any{"status": incident.FalsePositive}
+		if !inc.IsSolved() {
+			updates["solved_at"] = time.Now()
+		}
+
+		if err := tx.Model(inc).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		return addIncidentEvent(tx, inc.ID, models.IncidentEventFalsePositive, message, by)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetIncident(id)
+}
+
+func (db *Database) updateIncident(id string, update func(tx *gorm.DB, inc *models.Incident) error) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		var inc models.Incident
+		if err := tx.Where("id = ?", id).First(&inc).Error; err != nil {
+			return err
+		}
+
+		return update(tx, &inc)
+	})
+}
+
+const (
+	IncidentStateOpen     = "open"
+	IncidentStateResolved = "resolved"
+)
+
+// Sort keys accepted by ListIncidents, prefixed with "-" for descending order
+var incidentSortColumns = map[string]string{
+	"created_at": "created_at",
+	"solved_at":  "solved_at",
+	"type":       "type",
+}
+
+var ErrInvalidSort = errors.New("invalid sort key")
+
+// IncidentFilter selects incidents returned by ListIncidents. Zero values
+// do not filter.
+type IncidentFilter struct {
+	Monitor string // monitor ID, URL or name
+	Type    incident.Type
+	State   string // IncidentStateOpen or IncidentStateResolved
+	From    *time.Time
+	To      *time.Time
+	Sort    string
+	Limit   int
+	Offset  int
+}
+
+// ListIncidents returns the incidents matching the filter with their
+// monitor and duration, along with the total number of matches.
+func (db *Database) ListIncidents(filter IncidentFilter) ([]models.Incident, int64, error) {
+	sort := filter.Sort
+	if sort == "" {
+		sort = "-created_at"
+	}
+
+	direction := "ASC"
+	if sort[0] == '-' {
+		direction = "DESC"
+		sort = sort[1:]
+	}
+
+	column, ok := incidentSortColumns[sort]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: %s", ErrInvalidSort, filter.Sort)
+	}
+
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	query := db.DB.Model(&models.Incident{})
+	if filter.Monitor != "" {
+		query = query.Where("monitor_id IN (?)", db.DB.Model(&models.Monitor{}).
+			Select("id").
+			Where("id = ? OR url = ? OR name = ?", filter.Monitor, filter.Monitor, filter.Monitor))
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	switch filter.State {
+	case IncidentStateOpen:
+		query = query.Where("solved_at IS NULL")
+	case IncidentStateResolved:
+		query = query.Where("solved_at IS NOT NULL")
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count incidents: %w", err)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1 // No limit
+	}
+
+	var incidents []models.Incident
+	if err := query.
+		Preload("Monitor").
+		Order(fmt.Sprintf("%s %s, id", column, direction)).
+		Limit(limit).
+		Offset(filter.Offset).
+		Find(&incidents).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list incidents: %w", err)
+	}
+
+	now := time.Now()
+	for i := range incidents {
+		duration := int64(incidents[i].GetDuration(now).Seconds())
+		incidents[i].Duration = &duration
+	}
+
+	return incidents, total, nil
+}
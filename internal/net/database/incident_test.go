@@ -0,0 +1,94 @@
+package database
+
+import (
+	"testing"
+	"time"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestIncidentActions(t *testing.T) {
+	db, _ := InitializeTestDatabase()
+	db.DB.Create(&models.Monitor{
+		ID:        "monitor",
+		URL:       "https://example.com",
+		Incidents: []models.Incident{{ID: "open", Type: incident.Timeout}},
+	})
+
+	inc, err := db.AcknowledgeIncident("open", "alice", "")
+	assert.NoError(t, err)
+	assert.Equal(t, incident.OnInvestigation, inc.Status)
+	assert.Equal(t, "alice", inc.AcknowledgedBy)
+	assert.True(t, inc.IsAcknowledged())
+
+	inc, err = db.AddIncidentNote("open", "bob", "upstream provider outage")
+	assert.NoError(t, err)
+	assert.Len(t, inc.Events, 2)
+	assert.Equal(t, models.IncidentEventNote, inc.Events[1].Type)
+	assert.Equal(t, "upstream provider outage", inc.Events[1].Message)
+
+	inc, err = db.MarkIncidentFalsePositive("open", "alice", "")
+	assert.NoError(t, err)
+	assert.Equal(t, incident.FalsePositive, inc.Status)
+	assert.True(t, inc.IsSolved())
+
+	_, err = db.AcknowledgeIncident("open", "alice", "")
+	assert.ErrorIs(t, err, ErrIncidentSolved)
+
+	_, err = db.AddIncidentNote("missing", "alice", "note")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestListIncidents(t *testing.T) {
+	db, _ := InitializeTestDatabase()
+
+	now := time.Now()
+	solvedAt := now.Add(-time.Hour)
+	db.DB.Create(&models.Monitor{ID: "website", URL: "https://example.com", Name: "Website"})
+	db.DB.Create(&models.Monitor{ID: "api", URL: "https://api.example.com"})
+	db.DB.Create(&[]models.Incident{
+		{ID: "old", MonitorID: "website", Type: incident.Timeout, CreatedAt: now.Add(-3 * time.Hour), SolvedAt: &solvedAt},
+		{ID: "open", MonitorID: "website", Type: incident.UnexpectedStatusCode, CreatedAt: now.Add(-time.Hour), IncidentID: 42},
+		{ID: "other", MonitorID: "api", Type: incident.Timeout, CreatedAt: now.Add(-2 * time.Hour)},
+	})
+	from := now.Add(-150 * time.Minute)
+
+	testCases := []struct {
+		name     string
+		filter   IncidentFilter
+		expected []string
+		total    int64
+	}{
+		{"all newest first", IncidentFilter{}, []string{"open", "other", "old"}, 3},
+		{"by monitor name", IncidentFilter{Monitor: "Website"}, []string{"open", "old"}, 2},
+		{"by monitor url", IncidentFilter{Monitor: "https://api.example.com"}, []string{"other"}, 1},
+		{"by type", IncidentFilter{Type: incident.Timeout, Sort: "created_at"}, []string{"old", "other"}, 2},
+		{"open", IncidentFilter{State: IncidentStateOpen}, []string{"open", "other"}, 2},
+		{"resolved", IncidentFilter{State: IncidentStateResolved}, []string{"old"}, 1},
+		{"time range", IncidentFilter{From: &from}, []string{"open", "other"}, 2},
+		{"paginated", IncidentFilter{Limit: 1, Offset: 1}, []string{"other"}, 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			incidents, total, err := db.ListIncidents(tc.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.total, total)
+
+			var ids []string
+			for _, inc := range incidents {
+				ids = append(ids, inc.ID)
+			}
+			assert.Equal(t, tc.expected, ids)
+		})
+	}
+
+	incidents, _, _ := db.ListIncidents(IncidentFilter{State: IncidentStateResolved})
+	assert.Equal(t, int64(2*time.Hour/time.Second), *incidents[0].Duration)
+
+	_, _, err := db.ListIncidents(IncidentFilter{Sort: "description"})
+	assert.ErrorIs(t, err, ErrInvalidSort)
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"fmt"
+	"time"
+	"uptime-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func (db *Database) GetAllMaintenance() ([]models.Maintenance, error) {
+	var windows []models.Maintenance
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	if err := db.DB.Order("created_at").Find(&windows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
+
+	return windows, nil
+}
+
+// GetActiveMaintenance returns the first maintenance window covering the
+// monitor at time t, or nil if there is none.
+func (db *Database) GetActiveMaintenance(monitor models.Monitor, t time.Time) *models.Maintenance {
+	windows, err := db.GetAllMaintenance()
+	if err != nil {
+		return nil
+	}
+
+	for _, window := range windows {
+		if window.AppliesTo(monitor) && window.IsActive(t) {
+			return &window
+		}
+	}
+
+	return nil
+}
+
+// ReplaceMaintenance swaps every window from the given source with windows.
+// It is used to sync windows declared in the configuration file on startup.
+func (db *Database) ReplaceMaintenance(source string, windows []*models.Maintenance) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("source = ?", source).Delete(&models.Maintenance{}).Error; err != nil {
+			return fmt.Errorf("failed to delete maintenance windows: %w", err)
+		}
+
+		if len(windows) == 0 {
+			return nil
+		}
+
+		if err := tx.Create(windows).Error; err != nil {
+			return fmt.Errorf("failed to save maintenance windows: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteMaintenance removes a window by ID and returns gorm.ErrRecordNotFound
+// if it does not exist.
+func (db *Database) DeleteMaintenance(id string) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	result := db.DB.Where("id = ?", id).Delete(&models.Maintenance{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete maintenance window %s: %w", id, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,307 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"uptime-go/internal/models"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// MaxDatabaseBackups is the number of database backups kept next to the
+// database file, older ones are removed.
+const MaxDatabaseBackups = 5
+
+const backupTimeFormat = "20060102T150405.000000"
+
+var ErrSchemaTooNew = errors.New("database schema is newer than this build")
+
+var ErrBackupUnsupported = errors.New("PostgreSQL databases are not backed up by uptime-go, back them up with pg_dump")
+
+// Migration is a versioned change of the database schema. Down reverts Up,
+// both run in a transaction that also records the version.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(tx *gorm.DB) error
+	Down        func(tx *gorm.DB) error
+}
+
+// SchemaMigration is a migration applied to the database.
+type SchemaMigration struct {
+	Version     int `gorm:"primaryKey;autoIncrement:false"`
+	Description string
+	AppliedAt   time.Time
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus is a known migration and when it was applied, if it was.
+type MigrationStatus struct {
+	Version     int        `json:"version"`
+	Description string     `json:"description"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"`
+}
+
+func allModels() []any {
+	return []any{
+		&models.Monitor{},
+		&models.MonitorHistory{},
+		&models.Incident{},
+		&models.Maintenance{},
+		&models.IncidentEvent{},
+	}
+}
+
+// migrations are applied in order. Never change a released one.
+//
+// Version 1 is a baseline rather than a fixed schema: it creates whatever
+// AutoMigrate produces from the current models, and AutoMigrate runs again
+// after every migration run. Adding tables, columns and indexes to the models
+// is therefore applied without a migration, and the schema version only
+// records the changes AutoMigrate cannot make. Those need a new migration:
+// dropping or renaming tables, columns, indexes or constraints, changing
+// column types, adding constraints existing rows may violate, such as NOT
+// NULL or unique, and transforming data.
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "Create the initial schema",
+		// Baseline, see above
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(allModels()...)
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(allModels()...)
+		},
+	},
+	{
+		Version:     2,
+		Description: "Allow several monitors per URL",
+		Up: func(tx *gorm.DB) error {
+			// Monitors used to be unique by URL
+			if tx.Migrator().HasConstraint(&models.Monitor{}, "uni_monitors_url") {
+				if err := tx.Migrator().DropConstraint(&models.Monitor{}, "uni_monitors_url"); err != nil {
+					return err
+				}
+			}
+
+			return tx.Exec("DROP INDEX IF EXISTS uni_monitors_url").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			var urls []string
+			if err := tx.Model(&models.Monitor{}).Group("url").Having("COUNT(*) > 1").Pluck("url", &urls).Error; err != nil {
+				return err
+			}
+
+			if len(urls) > 0 {
+				return fmt.Errorf("several monitors use %s, remove them first", strings.Join(urls, ", "))
+			}
+
+			return tx.Exec("CREATE UNIQUE INDEX uni_monitors_url ON monitors(url)").Error
+		},
+	},
+}
+
+// LatestSchemaVersion is the schema version this build migrates to.
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// SchemaVersion returns the latest migration applied to the database, 0 when
+// none was.
+func (db *Database) SchemaVersion() (int, error) {
+	applied, err := db.appliedMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(applied) == 0 {
+		return 0, nil
+	}
+
+	return applied[len(applied)-1].Version, nil
+}
+
+// MigrationStatus lists the known migrations and the ones applied to the
+// database, oldest first.
+func (db *Database) MigrationStatus() ([]MigrationStatus, error) {
+	applied, err := db.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Version: migration.Version, Description: migration.Description}
+		if index := slices.IndexFunc(applied, func(m SchemaMigration) bool { return m.Version == migration.Version }); index >= 0 {
+			status.AppliedAt = &applied[index].AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Migrate applies the pending migrations and returns them. A SQLite database
+// file with tables is backed up first, a warning is logged for PostgreSQL.
+func (db *Database) Migrate() ([]MigrationStatus, error) {
+	applied, err := db.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range migrations {
+		if !slices.ContainsFunc(applied, func(m SchemaMigration) bool { return m.Version == migration.Version }) {
+			pending = append(pending, migration)
+		}
+	}
+
+	if len(pending) > 0 && db.DB.Migrator().HasTable(&models.Monitor{}) {
+		if _, err := db.Backup(); errors.Is(err, ErrBackupUnsupported) {
+			log.Warn().Int("pending", len(pending)).Msg("migrating without a backup, " + err.Error())
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	var done []MigrationStatus
+	for _, migration := range pending {
+		appliedAt := time.Now().UTC()
+		err := db.DB.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+
+			return tx.Create(&SchemaMigration{Version: migration.Version, Description: migration.Description, AppliedAt: appliedAt}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("failed to apply migration %d (%s): %w", migration.Version, migration.Description, err)
+		}
+
+		log.Info().Int("version", migration.Version).Str("description", migration.Description).Msg("migration applied")
+		done = append(done, MigrationStatus{Version: migration.Version, Description: migration.Description, AppliedAt: &appliedAt})
+	}
+
+	if err := db.DB.AutoMigrate(allModels()...); err != nil {
+		return done, fmt.Errorf("failed to migrate database schema: %w", err)
+	}
+
+	return done, nil
+}
+
+// Rollback reverts the migrations applied after version, newest first, and
+// returns them. The database is backed up first when backup is set, which
+// fails with ErrBackupUnsupported for PostgreSQL.
+func (db *Database) Rollback(version int, backup bool) ([]MigrationStatus, error) {
+	if version < 0 {
+		return nil, fmt.Errorf("invalid schema version %d", version)
+	}
+
+	applied, err := db.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var reverting []Migration
+	for _, m := range slices.Backward(applied) {
+		if m.Version <= version {
+			break
+		}
+
+		index := slices.IndexFunc(migrations, func(migration Migration) bool { return migration.Version == m.Version })
+		reverting = append(reverting, migrations[index])
+	}
+
+	if len(reverting) == 0 {
+		return nil, nil
+	}
+
+	if backup {
+		if _, err := db.Backup(); err != nil {
+			return nil, err
+		}
+	}
+
+	var done []MigrationStatus
+	for _, migration := range reverting {
+		err := db.DB.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+
+			return tx.Delete(&SchemaMigration{Version: migration.Version}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("failed to revert migration %d (%s): %w", migration.Version, migration.Description, err)
+		}
+
+		log.Info().Int("version", migration.Version).Str("description", migration.Description).Msg("migration reverted")
+		done = append(done, MigrationStatus{Version: migration.Version, Description: migration.Description})
+	}
+
+	return done, nil
+}
+
+// Backup copies the database next to its file as <file>.<time>.bak and
+// returns the path of the copy, empty for in-memory databases. PostgreSQL
+// databases return ErrBackupUnsupported.
+func (db *Database) Backup() (string, error) {
+	if _, ok := db.backend.(postgresBackend); ok {
+		return "", ErrBackupUnsupported
+	}
+
+	if db.path == "" {
+		return "", nil
+	}
+
+	path := fmt.Sprintf("%s.%s.bak", db.path, time.Now().UTC().Format(backupTimeFormat))
+	if err := db.DB.Exec("VACUUM INTO ?", path).Error; err != nil {
+		return "", fmt.Errorf("failed to back up database: %w", err)
+	}
+
+	log.Info().Str("backup", path).Msg("database backed up")
+
+	backups, err := filepath.Glob(db.path + ".*.bak")
+	if err != nil {
+		return path, nil
+	}
+
+	// The timestamps sort chronologically
+	slices.Sort(backups)
+	for _, old := range backups[:max(len(backups)-MaxDatabaseBackups, 0)] {
+		os.Remove(old)
+	}
+
+	return path, nil
+}
+
+// appliedMigrations returns the applied migrations, oldest first. It fails
+// when the database was migrated by a newer build.
+func (db *Database) appliedMigrations() ([]SchemaMigration, error) {
+	if err := db.DB.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("failed to create schema migrations table: %w", err)
+	}
+
+	var applied []SchemaMigration
+	if err := db.DB.Order("version").Find(&applied).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema migrations: %w", err)
+	}
+
+	for _, m := range applied {
+		if !slices.ContainsFunc(migrations, func(migration Migration) bool { return migration.Version == m.Version }) {
+			return nil, fmt.Errorf("%w: unknown migration %d (%s), latest supported is %d", ErrSchemaTooNew, m.Version, m.Description, LatestSchemaVersion())
+		}
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"uptime-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	MonitorStatusUp       = "up"
+	MonitorStatusDown     = "down"
+	MonitorStatusDegraded = "degraded"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks the position after which the next page starts. Histories are
+// paginated by creation time and ID, monitors by URL and ID.
+type Cursor struct {
+	Time time.Time
+	Key  string
+}
+
+// MonitorCursor returns the cursor of the page following monitor.
+func MonitorCursor(monitor models.Monitor) Cursor {
+	return Cursor{Key: monitor.URL + "\n" + monitor.ID}
+}
+
+// Encode keeps the UTC offset of the time, as timestamps are stored and
+// compared as text.
+func (c Cursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.Time.Format(time.RFC3339Nano) + "|" + c.Key))
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	timestamp, key, found := strings.Cut(string(raw), "|")
+	if !found {
+		return nil, ErrInvalidCursor
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{Time: t, Key: key}, nil
+}
+
+// MonitorFilter selects monitors returned by ListMonitors, ordered by URL and
+// ID.
+// Zero values do not filter.
+type MonitorFilter struct {
+	Status string // MonitorStatusUp, MonitorStatusDown or MonitorStatusDegraded
+	Group  string
+	Tag    string
+	Labels map[string]string // every label must match
+	After  *Cursor
+	Limit  int
+}
+
+// HistoryFilter selects the histories returned with a monitor, newest first.
+// Zero values do not filter.
+type HistoryFilter struct {
+	From  *time.Time
+	To    *time.Time
+	After *Cursor
+	Limit int
+}
+
+func (db *Database) ListMonitors(filter MonitorFilter) ([]models.Monitor, error) {
+	var monitors []models.Monitor
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	query := db.DB.Order("url, id")
+	switch filter.Status {
+	case MonitorStatusUp:
+		query = query.Where("is_up = ? AND (is_degraded IS NULL OR is_degraded = ?)", true, false)
+	case MonitorStatusDegraded:
+		query = query.Where("is_up = ? AND is_degraded = ?", true, true)
+	case MonitorStatusDown:
+		query = query.Where("is_up = ?", false)
+	}
+	if filter.Group != "" {
+		query = query.Where("group_name = ?", filter.Group)
+	}
+	if filter.Tag != "" {
+		query = query.Where(db.backend.hasTag("monitors.tags"), filter.Tag)
+	}
+	for name, value := range filter.Labels {
+		query = query.Where(db.backend.hasLabel("monitors.labels"), name, value)
+	}
+	if filter.After != nil {
+		url, id, _ := strings.Cut(filter.After.Key, "\n")
+		query = query.Where("url > ? OR (url = ? AND id > ?)", url, url, id)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	if err := query.Find(&monitors).Error; err != nil {
+		return nil, fmt.Errorf("failed to list monitors: %w", err)
+	}
+
+	return monitors, nil
+}
+
+// GetMonitorWithHistories returns a monitor by ID or URL with the histories
+// matching the filter, or gorm.ErrRecordNotFound if there is none. The first
+// monitor by ID is returned when several monitors share the URL.
+func (db *Database) GetMonitorWithHistories(idOrURL string, filter HistoryFilter) (*models.Monitor, error) {
+	var monitor models.Monitor
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	if err := db.DB.
+		Preload("Histories", func(tx *gorm.DB) *gorm.DB {
+			tx = tx.Order("monitor_histories.created_at DESC, monitor_histories.id DESC")
+			if filter.From != nil {
+				tx = tx.Where("monitor_histories.created_at >= ?", filter.From)
+			}
+			if filter.To != nil {
+				tx = tx.Where("monitor_histories.created_at < ?", filter.To)
+			}
+			if filter.After != nil {
+				tx = tx.Where("monitor_histories.created_at < ? OR (monitor_histories.created_at = ? AND monitor_histories.id < ?)",
+					filter.After.Time, filter.After.Time, filter.After.Key)
+			}
+			if filter.Limit > 0 {
+				tx = tx.Limit(filter.Limit)
+			}
+			return tx
+		}).
+		Where("id = ? OR url = ?", idOrURL, idOrURL).
+		First(&monitor).Error; err != nil {
+		return nil, err
+	}
+
+	return &monitor, nil
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"fmt"
+	"time"
+	"uptime-go/internal/models"
+)
+
+// DailyUptime holds the check counts of a monitor for one UTC day.
+type DailyUptime struct {
+	MonitorID string
+	Day       string // YYYY-MM-DD
+	Total     int64
+	Up        int64
+}
+
+func (d DailyUptime) Percentage() float64 {
+	if d.Total == 0 {
+		return 0
+	}
+
+	return float64(d.Up) / float64(d.Total) * 100
+}
+
+func (db *Database) GetPublicMonitors() ([]models.Monitor, error) {
+	var monitors []models.Monitor
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	if err := db.DB.Where("public = ?", true).Order("group_name, name, url").Find(&monitors).Error; err != nil {
+		return nil, fmt.Errorf("failed to get public monitors: %w", err)
+	}
+
+	return monitors, nil
+}
+
+// GetDailyUptime returns per-day check counts since the given time. Checks
+// recorded during maintenance are excluded.
+func (db *Database) GetDailyUptime(monitorIDs []string, since time.Time) ([]DailyUptime, error) {
+	var days []DailyUptime
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	day := db.backend.day("created_at")
+	if err := db.DB.Model(&models.MonitorHistory{}).
+		Select("monitor_id, "+day+" AS day, COUNT(*) AS total, COALESCE(SUM(CASE WHEN is_up THEN 1 ELSE 0 END), 0) AS up").
+		Where("monitor_id IN ? AND created_at >= ? AND in_maintenance = ?", monitorIDs, since, false).
+		Group("monitor_id, " + day).
+		Scan(&days).Error; err != nil {
+		return nil, fmt.Errorf("failed to get daily uptime: %w", err)
+	}
+
+	return days, nil
+}
+
+// GetIncidentsForMonitors returns open incidents and incidents created since
+// the given time for the monitors, newest first.
+func (db *Database) GetIncidentsForMonitors(monitorIDs []string, since time.Time, limit int) ([]models.Incident, error) {
+	var incidents []models.Incident
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+
+	if err := db.DB.
+		Preload("Monitor").
+		Where("monitor_id IN ?", monitorIDs).
+		Where(db.DB.Where("solved_at IS NULL").Or("created_at >= ?", since)).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&incidents).Error; err != nil {
+		return nil, fmt.Errorf("failed to get incidents: %w", err)
+	}
+
+	return incidents, nil
+}
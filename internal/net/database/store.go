@@ -0,0 +1,51 @@
+package database
+
+import (
+	"time"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+)
+
+// Store is the storage used by the monitor and the API. Database implements
+// it on SQLite and PostgreSQL.
+type Store interface {
+	// Records
+	Upsert(record any) error
+	UpsertRecord(record any, column string, updateColumn *[]string) error
+
+	// Monitors
+	GetAllMonitors() ([]models.Monitor, error)
+	GetMonitor(idOrName string) (*models.Monitor, error)
+	GetMonitorsByRef(refs []string) ([]models.Monitor, error)
+	GetMonitorWithHistories(idOrURL string, filter HistoryFilter) (*models.Monitor, error)
+	GetPublicMonitors() ([]models.Monitor, error)
+	ListMonitors(filter MonitorFilter) ([]models.Monitor, error)
+	AdoptMonitorIDs(monitors []*models.Monitor) error
+
+	// Histories
+	GetRecentHistories(monitorID string, limit int) ([]models.MonitorHistory, error)
+	GetRecentSuccessfulHistories(monitorID string, limit int) ([]models.MonitorHistory, error)
+	GetHistoriesSince(monitorID string, since time.Time) ([]models.MonitorHistory, error)
+
+	// Incidents
+	GetIncident(id string) (*models.Incident, error)
+	GetLastIncident(monitorID string, incidentType incident.Type) *models.Incident
+	GetIncidentsForMonitors(monitorIDs []string, since time.Time, limit int) ([]models.Incident, error)
+	ListIncidents(filter IncidentFilter) ([]models.Incident, int64, error)
+	AddIncidentEvent(incidentID string, eventType string, message string, actor string) error
+	AcknowledgeIncident(id string, by string, note string) (*models.Incident, error)
+	AddIncidentNote(id string, by string, note string) (*models.Incident, error)
+	MarkIncidentFalsePositive(id string, by string, note string) (*models.Incident, error)
+
+	// Maintenance windows
+	GetAllMaintenance() ([]models.Maintenance, error)
+	GetActiveMaintenance(monitor models.Monitor, t time.Time) *models.Maintenance
+	ReplaceMaintenance(source string, windows []*models.Maintenance) error
+	DeleteMaintenance(id string) error
+
+	// Aggregates
+	GetUptime(monitorID string, since time.Time, excludeMaintenance bool) (*float64, error)
+	GetDailyUptime(monitorIDs []string, since time.Time) ([]DailyUptime, error)
+}
+
+var _ Store = (*Database)(nil)
@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"sync"
 	"time"
+	"uptime-go/internal/incident"
 )
 
 type NetworkConfig struct {
@@ -27,6 +28,7 @@ type CheckResults struct {
 	IsUp           bool
 	StatusCode     int
 	ErrorMessage   string
+	FailureType    incident.Type
 	SSLExpiredDate *time.Time
 }
 
@@ -65,6 +67,8 @@ func (nc *NetworkConfig) CheckWebsite() (*CheckResults, error) {
 	result.ResponseTime = responseTime
 
 	if err != nil {
+		result.FailureType = ClassifyError(err)
+
 		var opErr *net.OpError
 		if errors.Is(err, io.EOF) {
 			result.ErrorMessage = fmt.Sprintf("Connection closed prematurely (EOF) while fetching %s. This might indicate a server issue or an incomplete response.", nc.URL)
@@ -80,6 +84,9 @@ func (nc *NetworkConfig) CheckWebsite() (*CheckResults, error) {
 	success := resp.StatusCode >= 200 && resp.StatusCode < 300
 	result.IsUp = success
 	result.StatusCode = resp.StatusCode
+	if !success {
+		result.FailureType = incident.UnexpectedStatusCode
+	}
 
 	if tls := resp.TLS; tls != nil &&
 		tls.PeerCertificates != nil &&
@@ -0,0 +1,78 @@
+package net
+
+import (
+	"errors"
+	"fmt"
+	"uptime-go/internal/configuration"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+
+	"github.com/rs/zerolog/log"
+)
+
+// statusPayload is the status change of an incident sent to the local
+// notifiers, which do not know the master incident IDs.
+type statusPayload struct {
+	IncidentID string `json:"incident_id"`
+	URL        string `json:"url"`
+	Type       string `json:"type"`
+	Status     string `json:"status"`
+}
+
+// HasNotifiers reports whether local notifiers are configured.
+func HasNotifiers() bool {
+	return len(configuration.Config.Notifiers) > 0
+}
+
+// NotifyNotifiers sends an incident to the local notifiers, with the payload
+// sent to the master.
+func NotifyNotifiers(incident *models.Incident, severity incident.Severity, event string, attributes map[string]any) error {
+	if !HasNotifiers() {
+		return nil
+	}
+
+	if incident.Monitor.IsNotExists() {
+		return fmt.Errorf("incident monitor data is not properly initialized")
+	}
+
+	payload := newIncidentPayload(incident, severity, event, attributes)
+	if ipAddress, err := GetIPAddress(); err == nil {
+		payload.ServerIP = ipAddress
+	}
+
+	return notify(payload)
+}
+
+// UpdateNotifiersStatus sends the status of an incident to the local
+// notifiers.
+func UpdateNotifiersStatus(incident *models.Incident, status incident.Status) error {
+	if !HasNotifiers() {
+		return nil
+	}
+
+	return notify(statusPayload{
+		IncidentID: incident.ID,
+		URL:        incident.Monitor.URL,
+		Type:       string(incident.Type),
+		Status:     string(status),
+	})
+}
+
+// notify posts payload to every local notifier. Failures are joined, so one
+// notifier failing does not stop the others.
+func notify(payload any) error {
+	var errs []error
+	for _, notifier := range configuration.Config.Notifiers {
+		response, body, err := sendRequest("POST", notifier.URL, notifier.Headers, payload)
+		if err == nil && (response.StatusCode < 200 || response.StatusCode > 299) {
+			err = fmt.Errorf("received status code %d. Body: %s", response.StatusCode, string(body))
+		}
+
+		if err != nil {
+			log.Error().Err(err).Str("notifier", notifier.Name).Msg("webhook error")
+			errs = append(errs, fmt.Errorf("%s: %w", notifier.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,80 @@
+package net
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uptime-go/internal/configuration"
+	"uptime-go/internal/incident"
+	"uptime-go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifiers(t *testing.T) {
+	once.Do(func() { ipAddress = "192.0.2.1" })
+
+	var received []map[string]any
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		received = append(received, body)
+	}))
+	defer server.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	previous := configuration.Config
+	t.Cleanup(func() { configuration.Config = previous })
+	configuration.Config.Standalone = true
+	configuration.Config.Notifiers = []configuration.NotifierConfig{
+		{Name: "ops", URL: server.URL, Headers: map[string]string{"Authorization": "Bearer local"}},
+	}
+
+	inc := &models.Incident{
+		ID:          "incident",
+		Type:        incident.UnexpectedStatusCode,
+		Description: "Unexpected status code",
+		Monitor: models.Monitor{
+			URL:       "https://example.com",
+			Tags:      []string{"checkout"},
+			Labels:    map[string]string{"team": "web"},
+			CreatedAt: time.Now(),
+		},
+	}
+
+	_, err := NotifyIncident(inc, incident.CRITICAL, "website.down", nil)
+	assert.ErrorIs(t, err, ErrStandalone)
+	assert.ErrorIs(t, UpdateIncidentStatus(inc, incident.Resolved), ErrStandalone)
+	assert.Empty(t, received)
+
+	require.NoError(t, NotifyNotifiers(inc, incident.CRITICAL, "website.down", nil))
+	require.NoError(t, UpdateNotifiersStatus(inc, incident.Resolved))
+	require.Len(t, received, 2)
+	assert.Equal(t, "Bearer local", authorization)
+
+	assert.Equal(t, "192.0.2.1", received[0]["server_ip"])
+	assert.Equal(t, []any{"uptime", "monitoring", string(incident.UnexpectedStatusCode), "checkout"}, received[0]["tags"])
+	assert.Equal(t, map[string]any{"url": "https://example.com", "team": "web"}, received[0]["attributes"])
+	assert.Equal(t, map[string]any{
+		"incident_id": "incident",
+		"url":         "https://example.com",
+		"type":        string(incident.UnexpectedStatusCode),
+		"status":      string(incident.Resolved),
+	}, received[1])
+
+	// A failing notifier does not stop the others
+	configuration.Config.Notifiers = append([]configuration.NotifierConfig{{Name: "broken", URL: failing.URL}}, configuration.Config.Notifiers...)
+	err = UpdateNotifiersStatus(inc, incident.Resolved)
+	assert.ErrorContains(t, err, "broken: received status code 502")
+	assert.Len(t, received, 3)
+}
@@ -3,6 +3,7 @@ package net
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"maps"
@@ -22,17 +23,38 @@ type incidentResponse struct {
 	} `json:"data"`
 }
 
-func sendRequest(method string, url string, payload any) (*http.Response, []byte, error) {
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+// ErrStandalone is returned instead of reporting to the master when running
+// standalone.
+var ErrStandalone = errors.New("running standalone, master reporting is disabled")
+
+// incidentPayload is an incident as reported to the master and the local
+// notifiers.
+type incidentPayload struct {
+	ServerIP   string         `json:"server_ip"`
+	Module     string         `json:"module"`
+	Severity   string         `json:"severity"`
+	Message    string         `json:"message"`
+	Event      string         `json:"event"`
+	Tags       []string       `json:"tags"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
 
+// sendToMaster sends a request authenticated with the agent token.
+func sendToMaster(method string, url string, payload any) (*http.Response, []byte, error) {
 	token := configuration.Config.Agent.Auth.Token
 	if token == "" {
 		log.Error().Msg("invalid server token")
 		return nil, nil, fmt.Errorf("error creating request for %s: invalid server token", url)
 	}
 
+	return sendRequest(method, url, map[string]string{"Authorization": "Bearer " + token}, payload)
+}
+
+func sendRequest(method string, url string, headers map[string]string, payload any) (*http.Response, []byte, error) {
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+
 	var body []byte
 	var err error
 	if payload != nil {
@@ -47,8 +69,10 @@ func sendRequest(method string, url string, payload any) (*http.Response, []byte
 		return nil, nil, fmt.Errorf("error creating request for %s: %w", url, err)
 	}
 
-	request.Header.Set("Authorization", "Bearer "+token)
 	request.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
 
 	response, err := client.Do(request)
 	if err != nil {
@@ -69,38 +93,20 @@ func NotifyIncident(incident *models.Incident, severity incident.Severity, event
 		return 0, fmt.Errorf("incident monitor data is not properly initialized")
 	}
 
+	if configuration.Config.Standalone {
+		return 0, ErrStandalone
+	}
+
 	ipAddress, err := GetIPAddress()
 	if err != nil {
 		log.Error().Err(err).Msgf("Failed to send incident notification for %s: failed to get server ip address", incident.Monitor.URL)
 		return 0, err
 	}
 
-	// Default attributes
-	attr := map[string]any{
-		"url": incident.Monitor.URL,
-	}
-
-	maps.Copy(attr, attributes)
-
-	payload := struct {
-		ServerIP   string         `json:"server_ip"`
-		Module     string         `json:"module"`
-		Severity   string         `json:"severity"`
-		Message    string         `json:"message"`
-		Event      string         `json:"event"`
-		Tags       []string       `json:"tags"`
-		Attributes map[string]any `json:"attributes,omitempty"`
-	}{
-		ServerIP:   ipAddress,
-		Module:     "UptimePlugin",
-		Severity:   string(severity),
-		Message:    incident.Description,
-		Event:      event,
-		Tags:       []string{"uptime", "monitoring", string(incident.Type)},
-		Attributes: attr,
-	}
+	payload := newIncidentPayload(incident, severity, event, attributes)
+	payload.ServerIP = ipAddress
 
-	response, body, err := sendRequest("POST", configuration.GetIncidentCreateURL(), payload)
+	response, body, err := sendToMaster("POST", configuration.GetIncidentCreateURL(), payload)
 	if err != nil {
 		log.Error().Err(err).Msgf("Failed to send incident notification for %s", incident.Monitor.URL)
 		return 0, err
@@ -124,8 +130,13 @@ func NotifyIncident(incident *models.Incident, severity incident.Severity, event
 }
 
 func UpdateIncidentStatus(incident *models.Incident, status incident.Status) error {
+	if configuration.Config.Standalone {
+		return ErrStandalone
+	}
+
 	if incident.IncidentID == 0 {
 		log.Error().Msgf("Failed to update incident status for %s: incident_id not set", incident.ID)
+		return fmt.Errorf("incident %s has no master incident_id", incident.ID)
 	}
 
 	payload := struct {
@@ -133,7 +144,7 @@ func UpdateIncidentStatus(incident *models.Incident, status incident.Status) err
 	}{Status: string(status)}
 
 	url := configuration.GetIncidentStatusURL(incident.IncidentID)
-	response, body, err := sendRequest("POST", url, payload)
+	response, body, err := sendToMaster("POST", url, payload)
 	if err != nil {
 		log.Error().Err(err).Msgf("Failed to send status update for incident %d", incident.IncidentID)
 		return err
@@ -155,3 +166,40 @@ func UpdateIncidentStatus(incident *models.Incident, status incident.Status) err
 	log.Info().Msgf("Successfully updated status for incident %d to '%s'. Message: %s", incident.IncidentID, status, result.Message)
 	return nil
 }
+
+// newIncidentPayload builds the payload of an incident, without the server
+// IP address.
+func newIncidentPayload(incident *models.Incident, severity incident.Severity, event string, attributes map[string]any) incidentPayload {
+	// Default attributes
+	attr := map[string]any{
+		"url": incident.Monitor.URL,
+	}
+
+	maps.Copy(attr, attributes)
+
+	tags := []string{"uptime", "monitoring", string(incident.Type)}
+
+	// Groups route their incidents with additional tags
+	if group := incident.Monitor.Group; group != "" {
+		attr["group"] = group
+		tags = append(tags, configuration.GetGroupConfig(group).Notify.Tags...)
+	}
+
+	// Tags and labels of the monitor, labels do not override the attributes
+	// set above
+	tags = append(tags, incident.Monitor.Tags...)
+	for name, value := range incident.Monitor.Labels {
+		if _, exists := attr[name]; !exists {
+			attr[name] = value
+		}
+	}
+
+	return incidentPayload{
+		Module:     "UptimePlugin",
+		Severity:   string(severity),
+		Message:    incident.Description,
+		Event:      event,
+		Tags:       tags,
+		Attributes: attr,
+	}
+}